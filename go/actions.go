@@ -0,0 +1,68 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// ErrInvalidAction is returned when an action has no label or its URL is not
+// an absolute URL
+var ErrInvalidAction = errors.New("invalid action. must have a label and an absolute URL")
+
+// paramActions is the parameter of the tap-action menu. It is not part of
+// the official Bark server and only sent to servers advertising it.
+const paramActions = "actions"
+
+// Action is a choice of the menu shown when a notification is tapped
+type Action struct {
+	// Label is the text of the choice
+	Label string `json:"label"`
+
+	// URL is opened when the choice is picked. App URL schemes such as
+	// "shortcuts://" are allowed.
+	URL string `json:"url"`
+}
+
+// isValidAction reports whether action has a label and an absolute URL
+func isValidAction(action Action) bool {
+	u, err := url.Parse(action.URL)
+	return action.Label != "" && err == nil && u.Scheme != ""
+}
+
+// applyActions keeps options.Actions only when they can be delivered: over
+// POST to a server that lists "actions" in ServerInfo.Arguments. Otherwise
+// they are dropped and the first action's URL is used as the URL, unless
+// one is set.
+func (c *Client) applyActions(ctx context.Context, mode SendMode, options NotificationOptions) NotificationOptions {
+	if len(options.Actions) == 0 || (mode == SendModePOST && c.supportsActions(ctx)) {
+		return options
+	}
+
+	if options.URL == "" {
+		options.URL = options.Actions[0].URL
+	}
+	options.Actions = nil
+	c.warn(WarningActionsDropped, "dropped the actions, not supported by the server or over GET")
+	return options
+}
+
+// supportsActions reports whether the server advertises tap-action menus
+func (c *Client) supportsActions(ctx context.Context) bool {
+	// The cached server info is the primary server's
+	if _, ok := serverOverride(ctx); ok {
+		return false
+	}
+
+	info, err := c.cachedServerInfo(ctx)
+	if err != nil {
+		c.warnf(WarningCheckSkipped, "sending actions as url, server info unavailable: %v", err)
+		return false
+	}
+	for _, name := range info.Arguments {
+		if name == paramActions {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithStrictArguments rejects notifications that set a parameter the server
+// doesn't list in ServerInfo.Arguments, instead of letting an older server
+// silently ignore it. The list is probed once via ServerInfo and cached.
+// Notifications are sent unchecked when the server info can't be fetched or
+// doesn't list any arguments. The offending fields are reported as
+// ValidationErrors matching ErrUnsupportedFeature.
+func WithStrictArguments() Option {
+	return func(c *Client) {
+		c.strictArguments = true
+	}
+}
+
+// SupportedArguments returns the parameters the server advertised in the
+// cached ServerInfo, nil if ServerInfo hasn't been fetched yet or the server
+// doesn't advertise them
+func (c *Client) SupportedArguments() []string {
+	c.serverInfo.mu.Lock()
+	defer c.serverInfo.mu.Unlock()
+	if c.serverInfo.info == nil {
+		return nil
+	}
+	return append([]string(nil), c.serverInfo.info.Arguments...)
+}
+
+// usedArguments returns the names of the parameters options sets. Title and
+// body are accepted by every server and not included.
+func usedArguments(options NotificationOptions) []string {
+	var names []string
+	if options.Subtitle != "" {
+		names = append(names, "subtitle")
+	}
+	for _, p := range appendQueryParams(nil, options) {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+// checkArguments applies WithStrictArguments to options
+func (c *Client) checkArguments(ctx context.Context, options NotificationOptions) error {
+	// Like feature gating, the cached list is the primary server's
+	if _, ok := serverOverride(ctx); ok || !c.strictArguments {
+		return nil
+	}
+
+	used := usedArguments(options)
+	if len(used) == 0 {
+		return nil
+	}
+	info, err := c.cachedServerInfo(ctx)
+	if err != nil {
+		c.warnf(WarningCheckSkipped, "argument check skipped, server info unavailable: %v", err)
+		return nil
+	}
+	if len(info.Arguments) == 0 {
+		return nil
+	}
+
+	supported := make(map[string]bool, len(info.Arguments))
+	for _, name := range info.Arguments {
+		supported[name] = true
+	}
+	var errs []error
+	for _, name := range used {
+		if !supported[name] {
+			errs = append(errs, &ValidationError{
+				Field:  name,
+				Reason: "not supported by server",
+				Err:    fmt.Errorf("%w: server doesn't advertise %s", ErrUnsupportedFeature, name),
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
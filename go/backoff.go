@@ -0,0 +1,133 @@
+package bark
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// defaultMultiplier is the backoff growth factor used when none is set
+const defaultMultiplier = 2
+
+// Backoff describes exponentially growing delays, shared by retries, the
+// send queue and failover. Delays grow from BaseDelay by Multiplier per
+// attempt and never exceed MaxDelay, after jitter is applied.
+type Backoff struct {
+	// BaseDelay is the delay after the first attempt
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts
+	MaxDelay time.Duration
+
+	// Multiplier is the growth factor per attempt, defaults to 2. A value
+	// of 1 gives a constant delay.
+	Multiplier float64
+
+	// Jitter is the randomization strategy, defaults to JitterFull
+	Jitter JitterStrategy
+}
+
+// DefaultBackoff returns a backoff with a 500ms base delay, a 10s maximum
+// delay, a multiplier of 2 and full jitter
+func DefaultBackoff() Backoff {
+	return Backoff{
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+		Multiplier: defaultMultiplier,
+		Jitter:     JitterFull,
+	}
+}
+
+// Next returns how long to wait after the given attempt (starting at 1).
+// With JitterDecorrelated the previous delay is taken to be the un-jittered
+// delay of the previous attempt, since Next keeps no state.
+func (b Backoff) Next(attempt int) time.Duration {
+	return b.next(attempt, b.exponential(attempt-1))
+}
+
+// next returns the delay after the given attempt. prev is the previous
+// delay, used by JitterDecorrelated.
+func (b Backoff) next(attempt int, prev time.Duration) time.Duration {
+	base := b.BaseDelay
+	if base <= 0 {
+		return 0
+	}
+	maxDelay := b.maxDelay()
+	exp := b.exponential(attempt)
+
+	var d time.Duration
+	switch b.Jitter {
+	case JitterNone:
+		d = exp
+	case JitterEqual:
+		d = exp/2 + randDuration(exp-exp/2)
+	case JitterDecorrelated:
+		if prev < base {
+			prev = base
+		}
+		upper := prev * 3
+		if upper > maxDelay || upper < prev {
+			upper = maxDelay
+		}
+		d = base + randDuration(upper-base)
+	default:
+		d = randDuration(exp)
+	}
+
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// exponential returns the un-jittered delay after the given attempt
+func (b Backoff) exponential(attempt int) time.Duration {
+	base := b.BaseDelay
+	if base <= 0 || attempt < 1 {
+		return 0
+	}
+	maxDelay := b.maxDelay()
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+
+	// Stop growing once the cap is reached so large attempt numbers can't
+	// overflow
+	exp := base
+	for i := 1; i < attempt && exp < maxDelay; i++ {
+		grown := float64(exp) * multiplier
+		if grown >= float64(maxDelay) {
+			exp = maxDelay
+			break
+		}
+		exp = time.Duration(grown)
+	}
+	if exp > maxDelay {
+		exp = maxDelay
+	}
+	return exp
+}
+
+// maxDelay returns MaxDelay, raised to BaseDelay when it is unset or smaller
+func (b Backoff) maxDelay() time.Duration {
+	if b.MaxDelay <= 0 || b.MaxDelay < b.BaseDelay {
+		return b.BaseDelay
+	}
+	return b.MaxDelay
+}
+
+var (
+	jitterRandMu sync.Mutex
+	jitterRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randDuration returns a random duration in [0, n]
+func randDuration(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	jitterRandMu.Lock()
+	defer jitterRandMu.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(n) + 1))
+}
@@ -0,0 +1,59 @@
+package bark
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffJitterBounds(t *testing.T) {
+	const (
+		base     = 100 * time.Millisecond
+		maxDelay = 2 * time.Second
+	)
+
+	for _, tt := range []struct {
+		name   string
+		jitter JitterStrategy
+
+		// lower returns the smallest allowed delay for the un-jittered
+		// delay exp of an attempt
+		lower func(exp time.Duration) time.Duration
+	}{
+		{"none", JitterNone, func(exp time.Duration) time.Duration { return exp }},
+		{"full", JitterFull, func(time.Duration) time.Duration { return 0 }},
+		{"equal", JitterEqual, func(exp time.Duration) time.Duration { return exp / 2 }},
+		{"decorrelated", JitterDecorrelated, func(time.Duration) time.Duration { return base }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Backoff{BaseDelay: base, MaxDelay: maxDelay, Multiplier: 2, Jitter: tt.jitter}
+			for attempt := 1; attempt <= 10; attempt++ {
+				exp := b.exponential(attempt)
+				for i := 0; i < 100; i++ {
+					d := b.Next(attempt)
+					if d < 0 || d > maxDelay {
+						t.Fatalf("attempt %d: delay %v outside [0, %v]", attempt, d, maxDelay)
+					}
+					if lower := tt.lower(exp); d < lower {
+						t.Fatalf("attempt %d: delay %v below %v", attempt, d, lower)
+					}
+					if tt.jitter != JitterDecorrelated && d > exp {
+						t.Fatalf("attempt %d: delay %v above the exponential delay %v", attempt, d, exp)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffExponentialCapped(t *testing.T) {
+	b := Backoff{BaseDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2, Jitter: JitterNone}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if d := b.Next(i + 1); d != w {
+			t.Errorf("Next(%d) = %v, want %v", i+1, d, w)
+		}
+	}
+	if d := b.Next(1000); d != 5*time.Second {
+		t.Errorf("Next(1000) = %v, want the maximum delay", d)
+	}
+}
@@ -6,13 +6,17 @@ package bark
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,10 +39,55 @@ var (
 	// ErrEmptyBody is returned when notification body is not provided
 	ErrEmptyBody = errors.New("notification body cannot be empty")
 
+	// ErrBodyWithCiphertext is returned when both a plaintext body and a ciphertext are provided
+	ErrBodyWithCiphertext = errors.New("notification body and ciphertext cannot both be set")
+
 	// ErrInvalidLevel is returned when an invalid notification level is provided
 	ErrInvalidLevel = errors.New("invalid level value. must be one of: active, timeSensitive, passive, critical")
+
+	// ErrInvalidGroup is returned when the group contains control characters
+	// such as newlines, or is too long in strict mode
+	ErrInvalidGroup = errors.New("invalid group. must not contain control characters or exceed the maximum length")
+
+	// ErrEmptyID is returned when a notification id is required but not provided
+	ErrEmptyID = errors.New("notification id cannot be empty")
+
+	// ErrInvalidImageURL is returned when the image is not an absolute http(s) URL
+	ErrInvalidImageURL = errors.New("invalid image URL. must be an absolute http or https URL")
+
+	// ErrInvalidBadge is returned when the badge is negative
+	ErrInvalidBadge = errors.New("invalid badge. must not be negative")
+
+	// ErrInvalidVolume is returned when the volume is outside 0 to 10
+	ErrInvalidVolume = errors.New("invalid volume. must be between 0 and 10")
+
+	// ErrVolumeRequiresCritical is returned when a volume is set on a
+	// notification that is not critical, see WithAllowVolumeAllLevels
+	ErrVolumeRequiresCritical = errors.New("volume is only supported for critical notifications")
+)
+
+// Parameter names understood by the Bark server. They must stay in sync with
+// the JSON tags of NotificationOptions, which carry the same parameters over POST.
+const (
+	paramURL        = "url"
+	paramGroup      = "group"
+	paramID         = "id"
+	paramIcon       = "icon"
+	paramImage      = "image"
+	paramSound      = "sound"
+	paramCall       = "call"
+	paramLevel      = "level"
+	paramVolume     = "volume"
+	paramBadge      = "badge"
+	paramIsArchive  = "isArchive"
+	paramCopy       = "copy"
+	paramCiphertext = "ciphertext"
+	paramIV         = "iv"
 )
 
+// defaultSuccessStatusCodes are the HTTP status codes accepted as a successful push
+var defaultSuccessStatusCodes = []int{http.StatusOK, http.StatusAccepted, http.StatusNoContent}
+
 // BarkError represents an error returned by the Bark API
 type BarkError struct {
 	// Message is the error message
@@ -49,31 +98,259 @@ type BarkError struct {
 
 	// Response is the raw response data
 	Response *Response
+
+	// RequestID is the X-Request-ID sent with the failed request, if any
+	RequestID string
+
+	// Body is the raw response body, up to 4 KiB, when the server answered
+	// with an error status or a body that couldn't be parsed
+	Body []byte
+
+	// Kind is the sentinel error classifying the failure, such as
+	// ErrBadParameters or ErrServerBusy for server response codes, or ErrDNS.
+	// Nil when the failure is not classified.
+	Kind error
+
+	// Err is the underlying error, such as the transport error of a failed
+	// request, if any
+	Err error
+
+	// transport is set when Err comes from sending the request, which then
+	// got no response, rather than from the client itself
+	transport bool
 }
 
 // Error implements the error interface
 func (e *BarkError) Error() string {
+	msg := e.Message
 	if e.StatusCode > 0 {
-		return fmt.Sprintf("%s (Status code: %d)", e.Message, e.StatusCode)
+		msg = fmt.Sprintf("%s (Status code: %d)", msg, e.StatusCode)
+	}
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s [request id: %s]", msg, e.RequestID)
 	}
-	return e.Message
+	return msg
+}
+
+// Is reports whether target is the sentinel error classifying this error,
+// so callers can branch with errors.Is(err, bark.ErrServerBusy)
+func (e *BarkError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// Unwrap returns the underlying error
+func (e *BarkError) Unwrap() error {
+	return e.Err
 }
 
-// Client represents a Bark notification client
+// Client represents a Bark notification client. It is safe for concurrent
+// use. Once it is shared, change its exported fields only with SetKey,
+// SetServerURL and SetHTTPClient.
 type Client struct {
 	// Key is your Bark key from the Bark iOS app
 	Key string
 
-	// ServerURL is the Bark server URL, defaults to DefaultServerURL
+	// ServerURL is the Bark server URL, defaults to DefaultServerURL. A
+	// unix:///path/to/socket URL passed to NewClient is rewritten to
+	// http://unix, with requests sent over the socket.
 	ServerURL string
 
-	// HTTPClient is the HTTP client used to make requests
+	// HTTPClient is the HTTP client used to make requests, unless an
+	// HTTPDoer is set with WithHTTPDoer
 	HTTPClient *http.Client
+
+	// doer replaces HTTPClient for sending requests when set
+	doer HTTPDoer
+
+	// h2c is set by WithHTTP2 when HTTP/2 cleartext was requested
+	h2c bool
+
+	// mu guards Key, ServerURL and HTTPClient, which may be changed with
+	// SetKey, SetServerURL and SetHTTPClient while sends are in flight
+	mu sync.RWMutex
+
+	// successStatusCodes are the HTTP status codes treated as success
+	successStatusCodes []int
+
+	// skipBodyCodeCheck disables validation of the JSON "code" field
+	skipBodyCodeCheck bool
+
+	// retryPolicy controls retries of failed sends, disabled by default
+	retryPolicy RetryPolicy
+
+	// maxRetries and retryBackoff override parts of retryPolicy when set
+	maxRetries   *int
+	retryBackoff *Backoff
+
+	// clock is the time source, replaceable for tests
+	clock Clock
+
+	// idempotency enables the Idempotency-Key header
+	idempotency bool
+
+	// beforeSend, afterSend and afterSendResult are the hooks run around
+	// each send
+	beforeSend      []BeforeSendHook
+	afterSend       []AfterSendHook
+	afterSendResult []AfterSendResultHook
+
+	// requestModifiers adjust each request just before it is sent
+	requestModifiers []RequestModifier
+
+	// queue holds notifications added with Enqueue, nil unless configured
+	queue *sendQueue
+
+	// deadLetter receives notifications that could not be delivered,
+	// deadLetters tracks the calls still running and deadLettersRunning
+	// counts them
+	deadLetter         DeadLetterFunc
+	deadLetters        sync.WaitGroup
+	deadLettersRunning atomic.Int32
+
+	// keepKeySuffix is the number of key characters left visible when redacting
+	keepKeySuffix int
+
+	// serverInfo caches the server's /info response
+	serverInfo serverInfoCache
+
+	// offlineVerify makes Verify skip pinging the server
+	offlineVerify bool
+
+	// laxKeyValidation only rejects keys that would break the request URL
+	laxKeyValidation bool
+
+	// closed is set once Shutdown has been called
+	closed atomic.Bool
+
+	// sendMode is the default send mode of SendWithResult
+	sendMode SendMode
+
+	// autoThreshold is the GET URL length above which SendModeAuto uses POST
+	autoThreshold int
+
+	// strictValidation rejects options that would otherwise be adjusted or
+	// passed through, such as over-long groups
+	strictValidation bool
+
+	// sanitizeBody strips control characters from bodies
+	sanitizeBody bool
+
+	// allowVolumeAllLevels accepts a volume on notifications of any level
+	allowVolumeAllLevels bool
+
+	// groupMaxLength is the maximum group length in characters, 0 for none
+	groupMaxLength int
+
+	// marshaler encodes POST bodies, encoding/json when nil
+	marshaler MarshalFunc
+
+	// rawErrors returns underlying errors instead of BarkError for failures
+	// that aren't reported by the server
+	rawErrors bool
+
+	// defaultLevel and defaultSound are applied to notifications that
+	// don't set their own
+	defaultLevel string
+	defaultSound string
+
+	// defaultArchive is applied to notifications without IsArchive, nil
+	// when not set
+	defaultArchive *bool
+
+	// autoGroup derives the group of notifications that don't set one
+	autoGroup func(options NotificationOptions) string
+
+	// paramOrder is the order of GET query parameters, sorted when nil
+	paramOrder []string
+
+	// queryStyleGET puts title, subtitle and body of GET requests in the
+	// query instead of the path
+	queryStyleGET bool
+
+	// featureGating checks notifications against the server version
+	featureGating FeatureGating
+
+	// strictArguments rejects parameters the server doesn't advertise
+	strictArguments bool
+
+	// logger receives warnings, the standard logger when nil
+	logger Logger
+
+	// warningHandler is told about sends changed or skipped in part
+	warningHandler func(Warning)
+
+	// debugLevel sets how much of each request is logged
+	debugLevel DebugLevel
+
+	// failoverServers are tried in order when the primary server fails,
+	// waiting failoverBackoff in between
+	failoverServers []string
+	failoverBackoff Backoff
+
+	// queueBackoff replaces DefaultBackoff for the queue's own retries
+	queueBackoff *Backoff
+
+	// retryBudget is the total retry wait time of a batch, unlimited when 0
+	retryBudget time.Duration
+
+	// sendTimeout bounds each logical send including retries, 0 for none
+	sendTimeout time.Duration
+
+	// pathTemplate replaces the standard request paths when set
+	pathTemplate string
+
+	// urlShortener shortens tap URLs before sending; a failure fails the
+	// send when urlShortenerRequired is set
+	urlShortener         URLShortener
+	urlShortenerRequired bool
+
+	// health is the background health check of the server
+	health healthCheck
+
+	// latency routes sends to the fastest server when enabled
+	latency latencyRouter
+
+	// onWait is told about backoffs and other waits of sends
+	onWait func(reason string, d time.Duration)
+
+	// breaker short-circuits requests during outages, nil when disabled
+	breaker *circuitBreaker
+
+	// disableOnInvalidKey stops sends with keys the server rejected, which
+	// are kept in disabledKeys
+	disableOnInvalidKey bool
+	disabledKeys        disabledKeys
+
+	// targets are the named recipient groups used by SendToGroup
+	targets targetGroups
+
+	// source tags notifications with their origin, see WithSource
+	source string
+
+	// contentType is the Content-Type of POST requests, the default when empty
+	contentType string
+
+	// acceptLanguage is the Accept-Language header of requests, not sent
+	// when empty
+	acceptLanguage string
+
+	// skipResponseParsing discards response bodies unread
+	skipResponseParsing bool
+
+	// testMode builds requests without sending them, see WithTestMode
+	testMode bool
+
+	// quietHours downgrades or suppresses notifications at night, nil when
+	// not set
+	quietHours *quietHours
+
+	// inFlight limits the requests in flight when set, see WithMaxConcurrency
+	inFlight chan struct{}
 }
 
 // NotificationOptions contains the options for a notification
 type NotificationOptions struct {
-	// Body is the main notification content (required)
+	// Body is the main notification content (required unless Ciphertext is set)
 	Body string `json:"body"`
 
 	// Title is the notification title
@@ -85,30 +362,168 @@ type NotificationOptions struct {
 	// URL to open when notification is tapped
 	URL string `json:"url,omitempty"`
 
+	// Actions is a menu of URLs to choose from when the notification is
+	// tapped. Only some forks of the Bark server support it; it is sent over
+	// POST to servers listing "actions" in ServerInfo.Arguments and
+	// otherwise replaced by the first action's URL when URL is unset.
+	Actions []Action `json:"actions,omitempty"`
+
 	// Group identifier for notifications
 	Group string `json:"group,omitempty"`
 
+	// ID identifies the notification on the device. Sending again with the
+	// same ID replaces the earlier notification, and Delete removes it.
+	ID string `json:"id,omitempty"`
+
 	// Icon is custom icon URL (iOS 15+ only)
 	Icon string `json:"icon,omitempty"`
 
+	// Image is the URL of a large image shown in the expanded notification.
+	// Requires a Bark app and server version that support the "image" parameter.
+	Image string `json:"image,omitempty"`
+
 	// Sound is custom notification sound
 	Sound string `json:"sound,omitempty"`
 
-	// Call plays sound repeatedly for 30 seconds if true
+	// Call plays sound repeatedly for 30 seconds if true. Without a Sound
+	// the device's default sound is repeated.
 	Call bool `json:"call,omitempty"`
 
 	// Level is notification importance level
 	// Values: "active", "timeSensitive", "passive", "critical"
 	Level string `json:"level,omitempty"`
 
-	// IsArchive defines whether to archive the notification
-	IsArchive bool `json:"isArchive,omitempty"`
+	// Volume is the volume of the sound from 0 to 10. The Bark app only
+	// honors it for critical notifications; for other levels it is
+	// rejected with ErrVolumeRequiresCritical unless WithAllowVolumeAllLevels
+	// is set. Nil uses the app's default volume.
+	Volume *int `json:"volume,omitempty"`
 
-	// Copy is text to copy to clipboard when notification is pressed
+	// Badge sets the app icon badge to this absolute number, it is not added
+	// to the current badge. Use Int(0) to clear the badge; nil leaves it
+	// unchanged. Bark has no relative badge mode.
+	Badge *int `json:"badge,omitempty"`
+
+	// IsArchive defines whether to archive the notification in the Bark
+	// app's history. Nil leaves it to the app's setting; use Bool(false) to
+	// keep a notification out of the history.
+	IsArchive *bool `json:"isArchive,omitempty"`
+
+	// Copy is text to copy to clipboard when notification is pressed.
+	// Sent as "copy" over both GET and POST.
 	Copy string `json:"copy,omitempty"`
 
-	// Ciphertext is encrypted notification content
+	// Ciphertext is encrypted notification content, replacing Body
 	Ciphertext string `json:"ciphertext,omitempty"`
+
+	// IV is the initialization vector the ciphertext was encrypted with, as
+	// returned by Encrypt. Leave empty when the IV is fixed in the Bark app.
+	IV string `json:"iv,omitempty"`
+}
+
+// Clone returns a deep copy of the options, safe to modify without
+// affecting the original
+func (o NotificationOptions) Clone() NotificationOptions {
+	clone := o
+	if o.Badge != nil {
+		clone.Badge = Int(*o.Badge)
+	}
+	if o.Volume != nil {
+		clone.Volume = Int(*o.Volume)
+	}
+	if o.Actions != nil {
+		clone.Actions = append([]Action(nil), o.Actions...)
+	}
+	if o.IsArchive != nil {
+		clone.IsArchive = Bool(*o.IsArchive)
+	}
+	return clone
+}
+
+// maxStringFieldLength is the number of characters of a text field shown by
+// NotificationOptions.String before it is truncated
+const maxStringFieldLength = 32
+
+// String returns a concise, log-safe summary of the options. Title and body
+// are truncated and encrypted content is never included.
+func (o NotificationOptions) String() string {
+	var b strings.Builder
+	b.WriteString("NotificationOptions{")
+
+	fields := 0
+	add := func(name, value string) {
+		if fields > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(value)
+		fields++
+	}
+
+	if o.Title != "" {
+		add("title", strconv.Quote(truncate(o.Title, maxStringFieldLength)))
+	}
+	if o.Subtitle != "" {
+		add("subtitle", strconv.Quote(truncate(o.Subtitle, maxStringFieldLength)))
+	}
+	if o.Body != "" {
+		add("body", strconv.Quote(truncate(o.Body, maxStringFieldLength)))
+	}
+	if o.Ciphertext != "" {
+		add("ciphertext", fmt.Sprintf("<%d bytes>", len(o.Ciphertext)))
+	}
+	if o.Level != "" {
+		add("level", o.Level)
+	}
+	if o.Volume != nil {
+		add("volume", strconv.Itoa(*o.Volume))
+	}
+	if o.Badge != nil {
+		add("badge", strconv.Itoa(*o.Badge))
+	}
+	if o.Group != "" {
+		add("group", strconv.Quote(o.Group))
+	}
+	if o.ID != "" {
+		add("id", strconv.Quote(o.ID))
+	}
+	if o.Sound != "" {
+		add("sound", o.Sound)
+	}
+	if o.URL != "" {
+		add("url", "set")
+	}
+	if len(o.Actions) > 0 {
+		add("actions", strconv.Itoa(len(o.Actions)))
+	}
+	if o.Icon != "" {
+		add("icon", "set")
+	}
+	if o.Image != "" {
+		add("image", "set")
+	}
+	if o.Copy != "" {
+		add("copy", "set")
+	}
+	if o.Call {
+		add("call", "true")
+	}
+	if o.IsArchive != nil {
+		add("archive", strconv.FormatBool(*o.IsArchive))
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// truncate shortens s to at most n characters, marking cut text with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
 }
 
 // Response represents a response from the Bark server
@@ -121,10 +536,76 @@ type Response struct {
 
 	// Data returned by the server, if any
 	Data interface{} `json:"data,omitempty"`
+
+	// Timestamp is the server time of the response in Unix seconds, if sent
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Latency is the time the request took, from sending it to reading the
+	// whole response
+	Latency time.Duration `json:"latency,omitempty"`
+
+	// TestMode marks a synthetic response of a client created with
+	// WithTestMode; nothing was sent
+	TestMode bool `json:"test_mode,omitempty"`
+
+	// TestRequest is the request that was built but not sent, set in test
+	// mode only
+	TestRequest *TestRequest `json:"test_request,omitempty"`
+}
+
+// JSON returns the response encoded as JSON, e.g. for storing send outcomes.
+// Decoding the result with encoding/json gives back an equal Response, with
+// Data decoded the way encoding/json decodes into interface{}.
+func (r *Response) JSON() ([]byte, error) {
+	return json.Marshal(r)
 }
 
-// NewClient creates a new Bark notification client
-func NewClient(key string, serverURL string) (*Client, error) {
+// SendResult describes the outcome of a single logical send
+type SendResult struct {
+	// Response is the parsed server response, nil if the send failed
+	Response *Response
+
+	// Latency is the total time spent sending, including all attempts
+	Latency time.Duration
+
+	// AttemptCount is the number of HTTP requests made for this send
+	AttemptCount int
+
+	// ServerURL is the Bark server the final attempt was sent to
+	ServerURL string
+
+	// Transport is the HTTP method the notification was sent with, which
+	// shows what SendModeAuto picked. Hooks added with WithAfterSendResult
+	// see it too.
+	Transport Transport
+
+	// RequestID is the X-Request-ID header sent with the request
+	RequestID string
+
+	// ServerRequestID is the X-Request-ID header returned by the server, if any
+	ServerRequestID string
+
+	// Deduplicated reports whether the server recognised the send as a
+	// replay of an earlier one with the same Idempotency-Key and answered
+	// with an "Idempotent-Replayed: true" header instead of delivering it
+	// again. Only servers supporting WithIdempotency set it.
+	Deduplicated bool
+
+	// Suppressed reports whether the notification was dropped by
+	// WithQuietHours instead of being sent. Response then carries no data
+	// from the server.
+	Suppressed bool
+}
+
+// NewClient creates a new Bark notification client.
+//
+// Surrounding whitespace, such as a trailing newline from a pasted key, is
+// trimmed from key before it is validated.
+func NewClient(key string, serverURL string, opts ...Option) (*Client, error) {
+	key = strings.TrimSpace(key)
 	if key == "" {
 		return nil, ErrEmptyKey
 	}
@@ -133,190 +614,753 @@ func NewClient(key string, serverURL string) (*Client, error) {
 		serverURL = DefaultServerURL
 	}
 
-	return &Client{
+	c := &Client{
 		Key:       key,
 		ServerURL: serverURL,
 		HTTPClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-	}, nil
+		successStatusCodes: defaultSuccessStatusCodes,
+		clock:              realClock{},
+		autoThreshold:      DefaultAutoThreshold,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.resolveRetryPolicy()
+
+	if err := c.useUnixSocket(); err != nil {
+		return nil, err
+	}
+	if err := c.validateHTTP2(); err != nil {
+		return nil, err
+	}
+	if err := c.validatePathTemplate(); err != nil {
+		return nil, err
+	}
+	if err := c.validateContentType(); err != nil {
+		return nil, err
+	}
+	if err := c.validateKey(c.Key); err != nil {
+		return nil, err
+	}
+	if err := c.validateDefaults(); err != nil {
+		return nil, err
+	}
+
+	c.startHealthCheck()
+	c.startLatencyRouting()
+	return c, nil
 }
 
 // Send sends a notification using GET request
 func (c *Client) Send(options NotificationOptions) (*Response, error) {
-	// Validate required fields
-	if options.Body == "" {
-		return nil, ErrEmptyBody
+	return c.sendGet(context.Background(), c.currentKey(), options)
+}
+
+// SendPost sends a notification using POST request
+func (c *Client) SendPost(options NotificationOptions) (*Response, error) {
+	return c.sendPost(context.Background(), c.currentKey(), options)
+}
+
+// SendWithResult sends a notification and returns a SendResult describing
+// how the send went. It uses the client's send mode, GET unless changed
+// with WithSendMode.
+//
+// The result is returned alongside the error whenever a request was actually
+// attempted, so diagnostics are available for failed sends too.
+func (c *Client) SendWithResult(ctx context.Context, options NotificationOptions) (*SendResult, error) {
+	return c.send(ctx, c.currentKey(), options, c.sendMode)
+}
+
+// SendEphemeral is like SendWithResult but never archives the notification
+// in the Bark app's history, whatever options.IsArchive says. It suits noisy,
+// transient status pings.
+func (c *Client) SendEphemeral(ctx context.Context, options NotificationOptions) (*SendResult, error) {
+	options.IsArchive = Bool(false)
+	return c.send(ctx, c.currentKey(), options, c.sendMode)
+}
+
+// SendTo is like SendWithResult for a given server and key instead of the
+// client's own, for multi-tenant setups sharing one client's retry, logging
+// and other settings. The server replaces the primary server for this send;
+// failover servers set with WithFailoverServers are still tried after it.
+// Health checks, latency routing and feature gating only concern the
+// client's own servers and don't apply. An empty serverURL uses the
+// client's servers.
+func (c *Client) SendTo(ctx context.Context, serverURL, key string, options NotificationOptions) (*SendResult, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if err := c.validateKey(key); err != nil {
+		return nil, err
+	}
+	if serverURL != "" {
+		if err := validateServerURL(serverURL); err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, serverOverrideKey{}, serverURL)
 	}
+	return c.send(ctx, key, options, c.sendMode)
+}
 
-	// Validate level if provided
-	if options.Level != "" && !isValidLevel(options.Level) {
-		return nil, ErrInvalidLevel
+// sendGet sends a notification to the given key using GET request
+func (c *Client) sendGet(ctx context.Context, key string, options NotificationOptions) (*Response, error) {
+	result, err := c.send(ctx, key, options, SendModeGET)
+	if err != nil {
+		return nil, err
 	}
+	return result.Response, nil
+}
 
-	// Build the endpoint URL
-	endpoint, err := c.buildEndpoint(options.Body, options.Title, options.Subtitle)
+// sendPost sends a notification to the given key using POST request
+func (c *Client) sendPost(ctx context.Context, key string, options NotificationOptions) (*Response, error) {
+	result, err := c.send(ctx, key, options, SendModePOST)
 	if err != nil {
 		return nil, err
 	}
+	return result.Response, nil
+}
 
-	// Prepare query parameters
-	params := url.Values{}
-	if options.URL != "" {
-		params.Add("url", options.URL)
+// send runs the send hooks around validating the options, sending them to
+// the given key with the given send mode and parsing the response
+func (c *Client) send(ctx context.Context, key string, options NotificationOptions, mode SendMode) (*SendResult, error) {
+	return c.runSend(ctx, options, func(ctx context.Context, options NotificationOptions) (*SendResult, error) {
+		return c.sendValidated(ctx, key, options, mode)
+	})
+}
+
+// runSend runs the send hooks and dead-letter reporting around sendOptions,
+// which validates and sends the options the before hooks returned
+func (c *Client) runSend(ctx context.Context, options NotificationOptions, sendOptions func(context.Context, NotificationOptions) (*SendResult, error)) (*SendResult, error) {
+	ctx, cancel := c.withSendTimeout(ctx)
+	defer cancel()
+
+	if err := c.runBeforeSend(ctx, &options); err != nil {
+		return nil, err
 	}
-	if options.Group != "" {
-		params.Add("group", options.Group)
+
+	result, err := sendOptions(ctx, options)
+	c.runAfterSend(ctx, result, err)
+
+	// A send that still fails after retrying is a dead letter
+	if err != nil && result != nil && c.retryPolicy.MaxAttempts >= 2 && !isQueuedSend(ctx) {
+		c.reportDeadLetter(options, err)
 	}
-	if options.Icon != "" {
-		params.Add("icon", options.Icon)
+	return result, err
+}
+
+// sendValidated prepares and validates the options and executes the send
+func (c *Client) sendValidated(ctx context.Context, key string, options NotificationOptions, mode SendMode) (*SendResult, error) {
+	options, mode, suppress, err := c.prepareSend(ctx, key, options, mode)
+	if err != nil {
+		return nil, err
 	}
-	if options.Sound != "" {
-		params.Add("sound", options.Sound)
+	if suppress {
+		return newSuppressedResult(), nil
 	}
-	if options.Call {
-		params.Add("call", "1")
+
+	result, err := c.execute(ctx, key, func(serverURL string) (*http.Request, error) {
+		return c.newRequest(ctx, mode, serverURL, key, options)
+	})
+	if result != nil {
+		result.Transport = mode.transport()
 	}
-	if options.Level != "" {
-		params.Add("level", options.Level)
+	return result, err
+}
+
+// prepareSend turns the options of a send into the options that are sent,
+// applying the source tag, silent mode, client defaults, quiet hours,
+// feature gating, URL shortening and actions, and validates them. It
+// returns the concrete send mode for mode, and suppress is true when quiet
+// hours drop the notification.
+func (c *Client) prepareSend(ctx context.Context, key string, options NotificationOptions, mode SendMode) (_ NotificationOptions, _ SendMode, suppress bool, err error) {
+	// Silent sends are made silent before validation, and again afterwards
+	// to drop the client's default sound
+	input := applySilent(ctx, c.applySource(ctx, options))
+	if options, err = c.prepareOptions(input); err != nil {
+		return options, mode, false, err
 	}
-	if options.IsArchive {
-		params.Add("isArchive", "1")
+	c.warnAdjusted(input, options)
+	options = applySilent(ctx, options)
+
+	if options, suppress = c.applyQuietHours(options); suppress {
+		return options, mode, true, nil
 	}
-	if options.Copy != "" {
-		params.Add("copy", options.Copy)
+	if options, err = c.gateFeatures(ctx, options); err != nil {
+		return options, mode, false, err
 	}
-	if options.Ciphertext != "" {
-		params.Add("ciphertext", options.Ciphertext)
+	if err = c.checkArguments(ctx, options); err != nil {
+		return options, mode, false, err
+	}
+	if options, err = c.shortenURL(ctx, options); err != nil {
+		return options, mode, false, err
+	}
+	mode = c.resolveMode(mode, key, options)
+	options = c.applyActions(ctx, mode, options)
+	return options, mode, false, nil
+}
+
+// execute sends the requests created by newReq for the given key, retrying
+// according to the client's retry policy and failing over to the servers set
+// with WithFailoverServers. newReq is called with the server of each attempt
+// since a request body can only be read once.
+func (c *Client) execute(ctx context.Context, key string, newReq func(serverURL string) (*http.Request, error)) (result *SendResult, err error) {
+	result = &SendResult{
+		RequestID: newRandomID(),
 	}
+	start := c.clock.Now()
 
-	// Build the final URL
-	requestURL := endpoint
-	if len(params) > 0 {
-		requestURL = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+	// The idempotency key identifies the logical send, so it stays the same
+	// for every retry of it
+	var idempotencyKey string
+	if c.idempotency {
+		idempotencyKey = newRandomID()
 	}
 
-	// Create the request
-	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	servers, err := c.sendServers(ctx)
 	if err != nil {
-		return nil, &BarkError{
-			Message: fmt.Sprintf("failed to create request: %v", err),
+		return nil, err
+	}
+
+	if c.keyDisabled(key) {
+		return nil, ErrKeyDisabled
+	}
+	if !c.allowRequest() {
+		return nil, ErrCircuitOpen
+	}
+	var local bool
+	defer func() {
+		if local {
+			c.releaseRequest()
+		} else {
+			c.recordOutcome(ctx, err)
+		}
+		c.recordKeyOutcome(key, err)
+	}()
+
+	var attempts []ServerAttempt
+	for i := 0; ; i++ {
+		serverURL := servers[i]
+		result.ServerURL = serverURL
+		ok, err := c.executeOn(ctx, key, serverURL, idempotencyKey, result, newReq)
+		if !ok {
+			// The request never left the client, which says nothing
+			// about the server
+			local = true
+			return nil, c.surfaceError(err, key)
+		}
+		err = c.surfaceError(err, key)
+		if err == nil {
+			result.Latency = c.clock.Now().Sub(start)
+			return result, nil
+		}
+
+		attempts = append(attempts, newServerAttempt(serverURL, err))
+		if i == len(servers)-1 || ctx.Err() != nil || !c.retryPolicy.retries(err) {
+			result.Latency = c.clock.Now().Sub(start)
+			if len(attempts) == 1 {
+				return result, interrupted(ctx, err)
+			}
+			return result, interrupted(ctx, &FailoverError{Attempts: attempts})
+		}
+
+		if sleepErr := c.wait(ctx, WaitFailover, c.failoverBackoff.Next(i+1)); sleepErr != nil {
+			result.Latency = c.clock.Now().Sub(start)
+			return result, interrupted(ctx, &FailoverError{Attempts: attempts})
 		}
 	}
+}
 
-	// Send the request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, &BarkError{
-			Message: fmt.Sprintf("request failed: %v", err),
+// executeOn sends to serverURL until an attempt succeeds or the retry policy
+// gives up, and returns the error of the last attempt. ok is false if no
+// request could be created or a request modifier failed.
+func (c *Client) executeOn(ctx context.Context, key, serverURL, idempotencyKey string, result *SendResult, newReq func(serverURL string) (*http.Request, error)) (ok bool, err error) {
+	maxAttempts := c.retryPolicy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		req, err := newReq(serverURL)
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("X-Request-ID", result.RequestID)
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		if err := c.modifyRequest(req); err != nil {
+			return false, err
+		}
+
+		result.AttemptCount++
+		var header http.Header
+		result.Response, header, err = c.do(req, key)
+		if header != nil {
+			result.ServerRequestID = header.Get("X-Request-ID")
+			result.Deduplicated = isReplayed(header)
+		}
+		if barkErr, ok := err.(*BarkError); ok {
+			// Transport errors quote the request URL, which contains the key
+			barkErr.Message = c.redact(barkErr.Message, key)
+			barkErr.RequestID = result.RequestID
+		}
+		if err == nil || attempt >= maxAttempts || ctx.Err() != nil || !c.retryPolicy.retries(err) {
+			return true, err
+		}
+
+		delay = c.retryPolicy.next(attempt, delay)
+		if !takeRetryBudget(ctx, delay) {
+			return true, err
+		}
+		if sleepErr := c.wait(ctx, WaitRetry, delay); sleepErr != nil {
+			return true, err
 		}
 	}
+}
+
+// do sends the request and parses the response. The response headers are
+// returned whenever the server answered. key is redacted from debug logs.
+func (c *Client) do(req *http.Request, key string) (*Response, http.Header, error) {
+	c.setAcceptLanguage(req)
+	c.logRequest(req, key)
+	if c.testMode {
+		response, err := c.testResponse(req)
+		return response, nil, err
+	}
+
+	if err := c.acquireSlot(req.Context()); err != nil {
+		return nil, nil, newTransportError(err)
+	}
+	defer c.releaseSlot()
+
+	start := c.clock.Now()
+	resp, err := c.currentDoer().Do(req)
+	logBody := c.logResponse(req, key, resp, err, c.clock.Now().Sub(start))
+	if err != nil {
+		return nil, nil, newTransportError(err)
+	}
 	defer resp.Body.Close()
 
-	// Parse the response
-	return parseResponse(resp)
+	response, err := c.parseResponse(resp)
+	logBody()
+
+	latency := c.clock.Now().Sub(start)
+	if response != nil {
+		response.StatusCode = resp.StatusCode
+		response.Latency = latency
+	}
+	var barkErr *BarkError
+	if errors.As(err, &barkErr) && barkErr.Response != nil {
+		barkErr.Response.StatusCode = resp.StatusCode
+		barkErr.Response.Latency = latency
+	}
+	return response, resp.Header, err
 }
 
-// SendPost sends a notification using POST request
-func (c *Client) SendPost(options NotificationOptions) (*Response, error) {
-	// Validate required fields
-	if options.Body == "" {
-		return nil, ErrEmptyBody
+// prepareOptions applies the client's defaults and adjustments to the
+// options, such as truncating the group, and validates the result
+func (c *Client) prepareOptions(options NotificationOptions) (NotificationOptions, error) {
+	c.applyDefaults(&options)
+	if c.sanitizeBody && options.Body != "" {
+		if options.Body = sanitizeBody(options.Body); options.Body == "" {
+			return options, &ValidationError{
+				Field:  "body",
+				Reason: "is empty after removing control characters",
+				Err:    ErrBodyEmptyAfterSanitize,
+			}
+		}
+	}
+	if c.groupMaxLength > 0 && !c.strictValidation {
+		options.Group = truncateRunes(options.Group, c.groupMaxLength)
+	}
+
+	if err := c.validateOptions(options); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+// validateOptions checks the options for missing or invalid values. Every
+// problem found is reported as a *ValidationError, joined when there are
+// several.
+func (c *Client) validateOptions(options NotificationOptions) error {
+	var errs []error
+	invalid := func(field, reason string, err error) {
+		errs = append(errs, &ValidationError{Field: field, Reason: reason, Err: err})
+	}
+
+	// Validate required fields. Encrypted notifications carry their body
+	// in the ciphertext, and carrying both leaves it to the server which wins.
+	if options.Body == "" && options.Ciphertext == "" {
+		invalid("body", "is required unless ciphertext is set", ErrEmptyBody)
+	}
+	if options.Body != "" && options.Ciphertext != "" {
+		invalid("ciphertext", "cannot be combined with body", ErrBodyWithCiphertext)
 	}
 
 	// Validate level if provided
 	if options.Level != "" && !isValidLevel(options.Level) {
-		return nil, ErrInvalidLevel
+		invalid("level", "must be one of: active, timeSensitive, passive, critical", ErrInvalidLevel)
 	}
 
-	// Prepare the request URL
-	requestURL := fmt.Sprintf("%s/%s", c.ServerURL, c.Key)
+	// Validate badge if provided
+	if options.Badge != nil && *options.Badge < 0 {
+		invalid("badge", "must not be negative", ErrInvalidBadge)
+	}
 
-	// Marshal the options to JSON
-	data, err := json.Marshal(options)
-	if err != nil {
-		return nil, &BarkError{
-			Message: fmt.Sprintf("failed to marshal request body: %v", err),
+	// Validate volume if provided
+	if options.Volume != nil {
+		if *options.Volume < 0 || *options.Volume > 10 {
+			invalid("volume", "must be between 0 and 10", ErrInvalidVolume)
+		} else if options.Level != LevelCritical && !c.allowVolumeAllLevels {
+			invalid("volume", "requires level critical", ErrVolumeRequiresCritical)
 		}
 	}
 
+	// Validate image URL if provided
+	if options.Image != "" && !isValidHTTPURL(options.Image) {
+		invalid("image", "must be an absolute http or https URL", ErrInvalidImageURL)
+	}
+
+	// Validate actions if provided
+	for i, action := range options.Actions {
+		if !isValidAction(action) {
+			invalid(paramActions, "must have a label and an absolute URL", fmt.Errorf("%w: action %d", ErrInvalidAction, i))
+		}
+	}
+
+	// Validate sound if provided
+	if options.Sound != "" {
+		if err := c.validateSound(options.Sound); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Validate call, which repeats the default sound when none is set
+	if options.Call && options.Sound == "" && c.strictValidation {
+		invalid("call", "requires a sound in strict mode", ErrCallWithoutSound)
+	}
+
+	// Validate group if provided
+	if options.Group != "" {
+		if err := c.validateGroup(options.Group); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// newRequest creates the HTTP request for sending options to the given key
+func (c *Client) newRequest(ctx context.Context, mode SendMode, serverURL, key string, options NotificationOptions) (*http.Request, error) {
+	if mode == SendModePOST {
+		return c.newPostRequest(ctx, serverURL, key, options)
+	}
+	return c.newGetRequest(ctx, serverURL, key, options)
+}
+
+// newGetRequest creates a GET request carrying the options in the URL
+func (c *Client) newGetRequest(ctx context.Context, serverURL, key string, options NotificationOptions) (*http.Request, error) {
+	requestURL, err := c.getURL(serverURL, key, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the request
-	req, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, &BarkError{
 			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
 		}
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Send the request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, &BarkError{
-			Message: fmt.Sprintf("request failed: %v", err),
+	return req, nil
+}
+
+// getURL returns the URL of a GET request carrying the options
+func (c *Client) getURL(serverURL, key string, options NotificationOptions) (string, error) {
+	var buf [maxQueryParams]queryParam
+	params := appendQueryParams(buf[:0], options)
+
+	// Path templates, custom parameter orders and query-style URLs go
+	// through url.Values
+	if c.pathTemplate != "" || c.paramOrder != nil || c.queryStyleGET {
+		return c.customURL(serverURL, key, options, params), nil
+	}
+
+	// Everything else is written in one go, with the parameters already
+	// sorted the way url.Values.Encode sorts them
+	size := len(serverURL) + len(key) + 4 + 3*(len(options.Title)+len(options.Subtitle)+len(options.Body))
+	for _, p := range params {
+		size += len(p.name) + 2 + 3*len(p.value)
+	}
+	var b strings.Builder
+	b.Grow(size)
+
+	writeEndpoint(&b, serverURL, key, options.Body, options.Title, options.Subtitle)
+	for i, p := range params {
+		if i == 0 {
+			b.WriteByte('?')
+		} else {
+			b.WriteByte('&')
 		}
+		b.WriteString(p.name)
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(p.value))
 	}
-	defer resp.Body.Close()
+	return b.String(), nil
+}
 
-	// Parse the response
-	return parseResponse(resp)
+// customURL returns the URL of a GET request for clients with a path
+// template, parameter order or query-style URLs
+func (c *Client) customURL(serverURL, key string, options NotificationOptions, params []queryParam) string {
+	values := make(url.Values, len(params)+3)
+	for _, p := range params {
+		values.Add(p.name, p.value)
+	}
+
+	var b strings.Builder
+	if c.queryStyleGET {
+		b.WriteString(serverURL)
+		b.WriteString(c.keyPath(key))
+		for _, f := range []struct{ name, value string }{
+			{"title", options.Title},
+			{"subtitle", options.Subtitle},
+			{"body", options.Body},
+		} {
+			if f.value != "" {
+				values.Set(f.name, normalizeLineBreaks(f.value))
+			}
+		}
+	} else if c.pathTemplate != "" {
+		path, rest := c.expandPath(key, options, true)
+		b.WriteString(serverURL)
+		b.WriteString(path)
+		for name := range rest {
+			values.Set(name, rest.Get(name))
+		}
+	} else {
+		writeEndpoint(&b, serverURL, key, options.Body, options.Title, options.Subtitle)
+	}
+
+	if len(values) > 0 {
+		b.WriteByte('?')
+		b.WriteString(c.encodeQuery(values))
+	}
+	return b.String()
 }
 
-// buildEndpoint builds the endpoint URL based on provided parameters
-func (c *Client) buildEndpoint(body, title, subtitle string) (string, error) {
-	baseURL := fmt.Sprintf("%s/%s", c.ServerURL, c.Key)
+// maxQueryParams is the number of parameters appendQueryParams may add
+const maxQueryParams = 14
 
-	// Safely encode parameters
-	var escapedBody, escapedTitle, escapedSubtitle string
-	var err error
+// queryParam is a single parameter of a GET query string
+type queryParam struct {
+	name, value string
+}
 
-	escapedBody = url.PathEscape(body)
-	if title != "" {
-		escapedTitle = url.PathEscape(title)
+// appendQueryParams appends the options carried in the query string of a GET
+// request to params, sorted by name. The names are the ones the JSON tags use
+// for POST, so the server sees the same parameters whichever transport is
+// used.
+func appendQueryParams(params []queryParam, options NotificationOptions) []queryParam {
+	if options.Badge != nil {
+		params = append(params, queryParam{paramBadge, strconv.Itoa(*options.Badge)})
+	}
+	if options.Call {
+		params = append(params, queryParam{paramCall, "1"})
+	}
+	if options.Ciphertext != "" {
+		params = append(params, queryParam{paramCiphertext, options.Ciphertext})
+	}
+	if options.Copy != "" {
+		params = append(params, queryParam{paramCopy, options.Copy})
+	}
+	if options.Group != "" {
+		params = append(params, queryParam{paramGroup, options.Group})
+	}
+	if options.Icon != "" {
+		params = append(params, queryParam{paramIcon, options.Icon})
+	}
+	if options.ID != "" {
+		params = append(params, queryParam{paramID, options.ID})
+	}
+	if options.Image != "" {
+		params = append(params, queryParam{paramImage, options.Image})
+	}
+	if options.IsArchive != nil {
+		archive := "0"
+		if *options.IsArchive {
+			archive = "1"
+		}
+		params = append(params, queryParam{paramIsArchive, archive})
+	}
+	if options.IV != "" {
+		params = append(params, queryParam{paramIV, options.IV})
+	}
+	if options.Level != "" {
+		params = append(params, queryParam{paramLevel, options.Level})
 	}
-	if subtitle != "" {
-		escapedSubtitle = url.PathEscape(subtitle)
+	if options.Sound != "" {
+		params = append(params, queryParam{paramSound, options.Sound})
+	}
+	if options.URL != "" {
+		params = append(params, queryParam{paramURL, options.URL})
 	}
+	if options.Volume != nil {
+		params = append(params, queryParam{paramVolume, strconv.Itoa(*options.Volume)})
+	}
+	return params
+}
+
+// newPostRequest creates a POST request carrying the options as a JSON body
+func (c *Client) newPostRequest(ctx context.Context, serverURL, key string, options NotificationOptions) (*http.Request, error) {
+	// Prepare the request URL
+	requestURL := serverURL + c.keyPath(key)
 
+	// Marshal the options to JSON
+	data, err := c.marshal(options)
 	if err != nil {
-		return "", &BarkError{
-			Message: fmt.Sprintf("failed to encode parameters: %v", err),
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to marshal request body: %v", err),
+			Err:     err,
 		}
 	}
 
-	if title != "" && subtitle != "" {
-		return fmt.Sprintf("%s/%s/%s/%s", baseURL, escapedTitle, escapedSubtitle, escapedBody), nil
-	} else if title != "" {
-		return fmt.Sprintf("%s/%s/%s", baseURL, escapedTitle, escapedBody), nil
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
+		}
+	}
+	req.Header.Set("Content-Type", c.postContentType())
+
+	return req, nil
+}
+
+// writeEndpoint writes the endpoint URL for the given text fields to b. A
+// subtitle is only placed in the path together with a title.
+func writeEndpoint(b *strings.Builder, serverURL, key, body, title, subtitle string) {
+	b.WriteString(serverURL)
+	b.WriteByte('/')
+	b.WriteString(key)
+
+	if title != "" {
+		b.WriteByte('/')
+		b.WriteString(escapePathSegment(title))
+		if subtitle != "" {
+			b.WriteByte('/')
+			b.WriteString(escapePathSegment(subtitle))
+		}
+	}
+	if body == "" {
+		// Encrypted notifications carry their content in the query
+		return
 	}
-	return fmt.Sprintf("%s/%s", baseURL, escapedBody), nil
+	b.WriteByte('/')
+	b.WriteString(escapePathSegment(body))
+}
+
+// escapePathSegment escapes a value for use as a single URL path segment.
+// Line breaks are normalized to "\n" and always encoded as %0A, so multi-line
+// bodies arrive with the same line breaks on every server.
+func escapePathSegment(value string) string {
+	return url.PathEscape(normalizeLineBreaks(value))
+}
+
+// normalizeLineBreaks replaces "\r\n" and "\r" line breaks with "\n"
+func normalizeLineBreaks(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.ReplaceAll(value, "\r", "\n")
 }
 
 // parseResponse parses the HTTP response into a Response struct
-func parseResponse(resp *http.Response) (*Response, error) {
+func (c *Client) parseResponse(resp *http.Response) (*Response, error) {
+	// Fire-and-forget sends only look at the status; the body is drained so
+	// the connection can be reused
+	if c.skipResponseParsing {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if !c.isSuccessStatus(resp.StatusCode) {
+			return nil, &BarkError{
+				Message:    "server returned error",
+				StatusCode: resp.StatusCode,
+				Kind:       errorForCode(resp.StatusCode, ""),
+			}
+		}
+		return &Response{}, nil
+	}
+
 	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
 		return nil, &BarkError{
 			Message:    fmt.Sprintf("failed to read response body: %v", err),
+			Err:        err,
 			StatusCode: resp.StatusCode,
 		}
 	}
 
 	// Check if the response was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, &BarkError{
+	if !c.isSuccessStatus(resp.StatusCode) {
+		barkErr := &BarkError{
 			Message:    fmt.Sprintf("server returned error: %s", strings.TrimSpace(string(body))),
 			StatusCode: resp.StatusCode,
+			Body:       capturedBody(body),
+			Kind:       errorForCode(resp.StatusCode, ""),
+		}
+
+		// Bark servers describe errors in a JSON body, which is more
+		// specific than the HTTP status
+		var response Response
+		if err := json.Unmarshal(body, &response); err == nil && response.Code != 0 {
+			barkErr.Message = fmt.Sprintf("API error: %s", response.Message)
+			barkErr.Response = &response
+			barkErr.Kind = errorForCode(response.Code, response.Message)
+		} else if isProxyChallenge(resp, body) {
+			barkErr.Message = "server answered with a proxy challenge page instead of the Bark API"
+			barkErr.Kind = ErrProxyChallenge
 		}
+		return nil, barkErr
+	}
+
+	// Accepted pushes without a body (e.g. 204 from a proxy) carry nothing to parse
+	if len(bytes.TrimSpace(body)) == 0 && resp.StatusCode != http.StatusOK {
+		return &Response{}, nil
+	}
+
+	// Without the body-level code check the HTTP status alone decides
+	// success, so minimal servers may answer with anything
+	if c.skipBodyCodeCheck {
+		var response Response
+		_ = json.Unmarshal(body, &response)
+		return &response, nil
 	}
 
 	// Parse the response
 	var response Response
 	if err := json.Unmarshal(body, &response); err != nil {
+		if isProxyChallenge(resp, body) {
+			return nil, &BarkError{
+				Message:    "server answered with an HTML page instead of the Bark API, likely a proxy or CDN challenge",
+				StatusCode: resp.StatusCode,
+				Body:       capturedBody(body),
+				Kind:       ErrProxyChallenge,
+			}
+		}
 		return nil, &BarkError{
 			Message:    fmt.Sprintf("failed to parse response: %v", err),
+			Err:        err,
 			StatusCode: resp.StatusCode,
+			Body:       capturedBody(body),
 		}
 	}
 
@@ -326,12 +1370,32 @@ func parseResponse(resp *http.Response) (*Response, error) {
 			Message:    fmt.Sprintf("API error: %s", response.Message),
 			StatusCode: resp.StatusCode,
 			Response:   &response,
+			Kind:       errorForCode(response.Code, response.Message),
 		}
 	}
 
 	return &response, nil
 }
 
+// isSuccessStatus checks if the HTTP status code indicates an accepted push
+func (c *Client) isSuccessStatus(code int) bool {
+	for _, successCode := range c.successStatusCodes {
+		if code == successCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidHTTPURL checks if the value is an absolute http or https URL
+func isValidHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // isValidLevel checks if the level value is valid
 func isValidLevel(level string) bool {
 	return level == LevelActive ||
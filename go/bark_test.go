@@ -0,0 +1,426 @@
+package bark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// successBody is the response of a Bark server to an accepted push
+const successBody = `{"code":200,"message":"success","timestamp":1700000000}`
+
+// newTestClient returns a client for the key "testkey" whose server is an
+// httptest server answering every request with handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("testkey", server.URL, opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// respondSuccess answers a request with a successful Bark response
+func respondSuccess(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.Copy(io.Discard, r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, successBody)
+}
+
+func TestSendWithResultDeduplicated(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"delivered", "", false},
+		{"replayed", "true", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Idempotency-Key") == "" {
+					t.Error("request without Idempotency-Key")
+				}
+				if tt.header != "" {
+					w.Header().Set("Idempotent-Replayed", tt.header)
+				}
+				respondSuccess(w, r)
+			}, WithIdempotency())
+
+			result, err := client.SendWithResult(context.Background(), NotificationOptions{Body: "hello"})
+			if err != nil {
+				t.Fatalf("SendWithResult: %v", err)
+			}
+			if result.Deduplicated != tt.want {
+				t.Errorf("Deduplicated = %v, want %v", result.Deduplicated, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendAcceptsEmptySuccessStatus(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusAccepted} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			})
+
+			response, err := client.Send(NotificationOptions{Body: "hello"})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if response.Code != 0 || response.Message != "" || response.Data != nil {
+				t.Errorf("response = %+v, want an empty response", response)
+			}
+			if response.StatusCode != status {
+				t.Errorf("StatusCode = %d, want %d", response.StatusCode, status)
+			}
+		})
+	}
+}
+
+func TestSendRejectsBodyWithCiphertext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+		respondSuccess(w, r)
+	})
+
+	for name, send := range map[string]func(NotificationOptions) (*Response, error){
+		"GET":  client.Send,
+		"POST": client.SendPost,
+	} {
+		_, err := send(NotificationOptions{Body: "plain", Ciphertext: "c2VjcmV0", IV: "0123456789abcdef"})
+		if !errors.Is(err, ErrBodyWithCiphertext) {
+			t.Errorf("%s: err = %v, want ErrBodyWithCiphertext", name, err)
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "ciphertext" {
+			t.Errorf("%s: err = %v, want a ValidationError for ciphertext", name, err)
+		}
+	}
+}
+
+func TestSendCiphertextWithTitle(t *testing.T) {
+	var gotPath, gotCiphertext string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotCiphertext = r.URL.Query().Get("ciphertext")
+		respondSuccess(w, r)
+	})
+
+	for _, tt := range []struct {
+		name     string
+		options  NotificationOptions
+		wantPath string
+	}{
+		{"untitled", NotificationOptions{Ciphertext: "c2VjcmV0"}, "/testkey"},
+		{"title", NotificationOptions{Title: "t", Ciphertext: "c2VjcmV0"}, "/testkey/t"},
+		{"subtitle", NotificationOptions{Title: "t", Subtitle: "s", Ciphertext: "c2VjcmV0"}, "/testkey/t/s"},
+	} {
+		if _, err := client.Send(tt.options); err != nil {
+			t.Fatalf("%s: Send: %v", tt.name, err)
+		}
+		if gotPath != tt.wantPath {
+			t.Errorf("%s: path = %q, want %q", tt.name, gotPath, tt.wantPath)
+		}
+		if gotCiphertext != "c2VjcmV0" {
+			t.Errorf("%s: ciphertext = %q, want %q", tt.name, gotCiphertext, "c2VjcmV0")
+		}
+	}
+}
+
+// receivedParams returns the parameters of a request as a Bark server reads
+// them: from the query of GET requests and the JSON body of POST requests
+func receivedParams(t *testing.T, r *http.Request) map[string]string {
+	t.Helper()
+	params := make(map[string]string)
+	if r.Method == http.MethodPost {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode POST body: %v", err)
+		}
+		for name, value := range body {
+			switch v := value.(type) {
+			case string:
+				params[name] = v
+			default:
+				data, _ := json.Marshal(v)
+				params[name] = string(data)
+			}
+		}
+		return params
+	}
+	for name := range r.URL.Query() {
+		params[name] = r.URL.Query().Get(name)
+	}
+	return params
+}
+
+func TestSendCopy(t *testing.T) {
+	const copyText = "code: 123 456 & more"
+	var got map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = receivedParams(t, r)
+		respondSuccess(w, r)
+	})
+
+	for name, send := range map[string]func(NotificationOptions) (*Response, error){
+		"GET":  client.Send,
+		"POST": client.SendPost,
+	} {
+		got = nil
+		if _, err := send(NotificationOptions{Body: "hello", Copy: copyText}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got["copy"] != copyText {
+			t.Errorf("%s: copy = %q, want %q", name, got["copy"], copyText)
+		}
+	}
+}
+
+func TestSendMultiLineBodyOverGET(t *testing.T) {
+	var path, escapedPath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path, escapedPath = r.URL.Path, r.URL.EscapedPath()
+		respondSuccess(w, r)
+	})
+
+	for _, body := range []string{"line one\nline two", "line one\r\nline two", "line one\rline two"} {
+		if _, err := client.Send(NotificationOptions{Body: body}); err != nil {
+			t.Fatalf("Send(%q): %v", body, err)
+		}
+		if want := "/testkey/line one\nline two"; path != want {
+			t.Errorf("Send(%q): path = %q, want %q", body, path, want)
+		}
+		if want := "/testkey/line%20one%0Aline%20two"; escapedPath != want {
+			t.Errorf("Send(%q): escaped path = %q, want %q", body, escapedPath, want)
+		}
+	}
+}
+
+func TestSendBadge(t *testing.T) {
+	var got map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = receivedParams(t, r)
+		respondSuccess(w, r)
+	})
+
+	for _, tt := range []struct {
+		name  string
+		badge *int
+		want  string
+		sent  bool
+	}{
+		{"unset", nil, "", false},
+		{"clear", Int(0), "0", true},
+		{"absolute", Int(7), "7", true},
+	} {
+		for method, send := range map[string]func(NotificationOptions) (*Response, error){
+			"GET":  client.Send,
+			"POST": client.SendPost,
+		} {
+			got = nil
+			if _, err := send(NotificationOptions{Body: "hello", Badge: tt.badge}); err != nil {
+				t.Fatalf("%s %s: %v", tt.name, method, err)
+			}
+			badge, sent := got["badge"]
+			if sent != tt.sent || badge != tt.want {
+				t.Errorf("%s %s: badge = %q (sent %v), want %q (sent %v)", tt.name, method, badge, sent, tt.want, tt.sent)
+			}
+		}
+	}
+}
+
+// legacyGetURL builds a GET URL the way the client did before it wrote URLs
+// with a strings.Builder: fmt.Sprintf for the path and url.Values for the
+// query. getURL must give the same result.
+func legacyGetURL(serverURL, key string, options NotificationOptions) string {
+	baseURL := fmt.Sprintf("%s/%s", serverURL, key)
+	body := escapePathSegment(options.Body)
+	title := escapePathSegment(options.Title)
+	subtitle := escapePathSegment(options.Subtitle)
+
+	var endpoint string
+	switch {
+	case options.Title != "" && options.Subtitle != "" && options.Body != "":
+		endpoint = fmt.Sprintf("%s/%s/%s/%s", baseURL, title, subtitle, body)
+	case options.Title != "" && options.Subtitle != "":
+		endpoint = fmt.Sprintf("%s/%s/%s", baseURL, title, subtitle)
+	case options.Title != "" && options.Body != "":
+		endpoint = fmt.Sprintf("%s/%s/%s", baseURL, title, body)
+	case options.Title != "":
+		endpoint = fmt.Sprintf("%s/%s", baseURL, title)
+	case options.Body != "":
+		endpoint = fmt.Sprintf("%s/%s", baseURL, body)
+	default:
+		endpoint = baseURL
+	}
+
+	params := url.Values{}
+	add := func(name, value string) {
+		if value != "" {
+			params.Add(name, value)
+		}
+	}
+	add(paramURL, options.URL)
+	add(paramGroup, options.Group)
+	add(paramID, options.ID)
+	add(paramIcon, options.Icon)
+	add(paramImage, options.Image)
+	add(paramSound, options.Sound)
+	if options.Call {
+		add(paramCall, "1")
+	}
+	add(paramLevel, options.Level)
+	if options.Volume != nil {
+		add(paramVolume, strconv.Itoa(*options.Volume))
+	}
+	if options.Badge != nil {
+		add(paramBadge, strconv.Itoa(*options.Badge))
+	}
+	if options.IsArchive != nil {
+		if *options.IsArchive {
+			add(paramIsArchive, "1")
+		} else {
+			add(paramIsArchive, "0")
+		}
+	}
+	add(paramCopy, options.Copy)
+	add(paramCiphertext, options.Ciphertext)
+	add(paramIV, options.IV)
+
+	if len(params) > 0 {
+		return fmt.Sprintf("%s?%s", endpoint, params.Encode())
+	}
+	return endpoint
+}
+
+// goldenOptions cover every parameter and the escaping of the text fields
+var goldenOptions = []NotificationOptions{
+	{Body: "hello"},
+	{Title: "Title", Body: "hello world"},
+	{Title: "Title", Subtitle: "Sub/title", Body: "50% done #1 ?"},
+	{Body: "line one\r\nline two", Group: "ops & dev"},
+	{Title: "Encrypted", Ciphertext: "c2VjcmV0+/=", IV: "0123456789abcdef"},
+	{Ciphertext: "c2VjcmV0"},
+	{
+		Title:     "Everything",
+		Subtitle:  "all fields",
+		Body:      "Grüße, 世界 😀",
+		URL:       "https://example.com/path?q=a b&x=1",
+		Group:     "alerts",
+		ID:        "id-1",
+		Icon:      "https://example.com/icon.png",
+		Image:     "https://example.com/image.png",
+		Sound:     "minuet",
+		Call:      true,
+		Level:     LevelCritical,
+		Volume:    Int(5),
+		Badge:     Int(0),
+		IsArchive: Bool(false),
+		Copy:      "copy me",
+	},
+	{Body: "archived", Badge: Int(12), IsArchive: Bool(true)},
+}
+
+func TestGetURLMatchesURLValues(t *testing.T) {
+	client, err := NewClient("testkey", "https://bark.test")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for _, options := range goldenOptions {
+		got, err := client.getURL(client.ServerURL, client.Key, options)
+		if err != nil {
+			t.Fatalf("getURL(%v): %v", options, err)
+		}
+		if want := legacyGetURL(client.ServerURL, client.Key, options); got != want {
+			t.Errorf("getURL(%v)\n got %s\nwant %s", options, got, want)
+		}
+	}
+}
+
+func BenchmarkBuildEndpoint(b *testing.B) {
+	client, err := NewClient("testkey", "https://bark.test")
+	if err != nil {
+		b.Fatalf("NewClient: %v", err)
+	}
+	options := goldenOptions[len(goldenOptions)-2]
+
+	b.Run("builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = client.getURL(client.ServerURL, client.Key, options)
+		}
+	})
+	b.Run("url.Values", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = legacyGetURL(client.ServerURL, client.Key, options)
+		}
+	})
+}
+
+// stubDoer answers every request with a successful Bark response without
+// any network, so benchmarks measure the client alone
+type stubDoer struct{}
+
+func (stubDoer) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(successBody)),
+		Request:    req,
+	}, nil
+}
+
+func BenchmarkSend(b *testing.B) {
+	client, err := NewClient("testkey", "https://bark.test", WithHTTPDoer(stubDoer{}))
+	if err != nil {
+		b.Fatalf("NewClient: %v", err)
+	}
+	options := goldenOptions[len(goldenOptions)-2]
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Send(options); err != nil {
+			b.Fatalf("Send: %v", err)
+		}
+	}
+}
+
+func BenchmarkSendResponseParsing(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"parsed", nil},
+		{"unparsed", []Option{WithoutResponseParsing()}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			client, err := NewClient("testkey", "https://bark.test", append(bm.opts, WithHTTPDoer(stubDoer{}))...)
+			if err != nil {
+				b.Fatalf("NewClient: %v", err)
+			}
+			options := NotificationOptions{Title: "Title", Body: "hello"}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.Send(options); err != nil {
+					b.Fatalf("Send: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package bark
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchConcurrency is the number of concurrent sends used by the
+// batch helpers when no explicit concurrency is given
+const defaultBatchConcurrency = 8
+
+// BatchResult is the outcome of sending a notification to a single key
+type BatchResult struct {
+	// Key is the Bark key the notification was sent to
+	Key string
+
+	// ID is the notification ID, set by DeleteBatch
+	ID string
+
+	// Body is the notification body, set by SendMany
+	Body string
+
+	// Response is the server response, nil if the send failed
+	Response *Response
+
+	// Err is the error for this key, nil on success
+	Err error
+
+	// State tells whether the send finished or was cut short by the
+	// cancellation of the batch, see SendBatchConcurrent
+	State BatchState
+}
+
+// BatchState tells how far a send of a batch got
+type BatchState int
+
+const (
+	// BatchCompleted means the send finished, successfully or not
+	BatchCompleted BatchState = iota
+
+	// BatchInterrupted means the send was in flight when the batch's context
+	// was done; the notification may or may not have been delivered
+	BatchInterrupted
+
+	// BatchNotStarted means the batch's context was done before the send
+	// started, so the notification was not sent
+	BatchNotStarted
+)
+
+// BatchResults are the per-key outcomes of a batch operation, in the order
+// of its keys
+type BatchResults []BatchResult
+
+// BatchSummary counts the outcomes of a batch operation
+type BatchSummary struct {
+	Total     int
+	Succeeded int
+	Failed    int
+}
+
+// Failures returns the results with an error, in their original order
+func (r BatchResults) Failures() BatchResults {
+	return r.filter(func(result BatchResult) bool { return result.Err != nil })
+}
+
+// Successes returns the results without an error, in their original order
+func (r BatchResults) Successes() BatchResults {
+	return r.filter(func(result BatchResult) bool { return result.Err == nil })
+}
+
+// Summary counts the successful and failed results
+func (r BatchResults) Summary() BatchSummary {
+	summary := BatchSummary{Total: len(r)}
+	for _, result := range r {
+		if result.Err != nil {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+	return summary
+}
+
+// filter returns the results for which keep returns true
+func (r BatchResults) filter(keep func(result BatchResult) bool) BatchResults {
+	filtered := BatchResults{}
+	for _, result := range r {
+		if keep(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// SendBatch sends the same notification to every key using POST request,
+// with up to 8 sends in flight at once.
+//
+// Each key gets its own copy of options, so nothing is shared between the
+// concurrent sends. Results are returned in the order of keys.
+func (c *Client) SendBatch(ctx context.Context, keys []string, options NotificationOptions) (BatchResults, error) {
+	return c.SendBatchConcurrent(ctx, keys, options, defaultBatchConcurrency)
+}
+
+// SendBatchConcurrent is like SendBatch with an explicit limit on the number
+// of sends in flight. A concurrency below 1 sends one key at a time.
+//
+// When ctx is done before the batch finished, the remaining keys are not
+// sent and the results are returned together with ctx.Err(). The State of
+// each result then tells the completed sends apart from those that were
+// interrupted in flight or never started.
+func (c *Client) SendBatchConcurrent(ctx context.Context, keys []string, options NotificationOptions, concurrency int) (BatchResults, error) {
+	if _, err := c.prepareOptions(options); err != nil {
+		return nil, err
+	}
+
+	ctx = c.withRetryBudget(ctx)
+	results := make(BatchResults, len(keys))
+	fanOut(len(keys), concurrency, func(i int) {
+		results[i].Key = keys[i]
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			results[i].State = BatchNotStarted
+			return
+		}
+		if keys[i] == "" {
+			results[i].Err = ErrEmptyKey
+			return
+		}
+		results[i].Response, results[i].Err = c.sendPost(ctx, keys[i], options.Clone())
+		if results[i].Err != nil && ctx.Err() != nil {
+			results[i].State = BatchInterrupted
+		}
+	})
+
+	for _, result := range results {
+		if result.State != BatchCompleted {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// SendBatchChunked sends the same notification to every key like SendBatch,
+// but in chunks of chunkSize keys. After each chunk fn is called with that
+// chunk's results, so they can be streamed elsewhere instead of held in
+// memory for the whole batch.
+//
+// Sending stops when fn returns an error, which is then returned, or when
+// ctx is done, returning ctx.Err(). A chunk cut short by ctx is still handed
+// to fn, with the State of its results set as by SendBatchConcurrent.
+func (c *Client) SendBatchChunked(ctx context.Context, keys []string, options NotificationOptions, chunkSize int, fn func(chunk []BatchResult) error) error {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	// All chunks share one retry budget
+	ctx = c.withRetryBudget(ctx)
+
+	for start := 0; start < len(keys); start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := start + chunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		results, err := c.SendBatch(ctx, keys[start:end], options)
+		if results == nil {
+			return err
+		}
+		if fnErr := fn(results); fnErr != nil {
+			return fnErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendMany sends a notification for every body to the client's key, each
+// with the other fields of template, such as its group, sound and level.
+// It is the counterpart of SendBatch for digests of many short messages to
+// one device. The body of template is ignored.
+//
+// Sends use POST requests with up to 8 in flight at once, and results are
+// returned in the order of bodies. Cancellation is handled as by
+// SendBatchConcurrent.
+func (c *Client) SendMany(ctx context.Context, bodies []string, template NotificationOptions) (BatchResults, error) {
+	return c.SendManyConcurrent(ctx, bodies, template, defaultBatchConcurrency)
+}
+
+// SendManyConcurrent is like SendMany with an explicit limit on the number
+// of sends in flight, e.g. to stay below a server's rate limit. A
+// concurrency below 1 sends one body at a time. WithMaxConcurrency caps the
+// client's requests across all sends on top of this.
+func (c *Client) SendManyConcurrent(ctx context.Context, bodies []string, template NotificationOptions, concurrency int) (BatchResults, error) {
+	// The template is validated once up front, with a stand-in for the
+	// bodies, which are validated by their own sends
+	check := template.Clone()
+	check.Body = "-"
+	if _, err := c.prepareOptions(check); err != nil {
+		return nil, err
+	}
+
+	key := c.currentKey()
+	ctx = c.withRetryBudget(ctx)
+	results := make(BatchResults, len(bodies))
+	fanOut(len(bodies), concurrency, func(i int) {
+		results[i].Key = key
+		results[i].Body = bodies[i]
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			results[i].State = BatchNotStarted
+			return
+		}
+		options := template.Clone()
+		options.Body = bodies[i]
+		results[i].Response, results[i].Err = c.sendPost(ctx, key, options)
+		if results[i].Err != nil && ctx.Err() != nil {
+			results[i].State = BatchInterrupted
+		}
+	})
+
+	for _, result := range results {
+		if result.State != BatchCompleted {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// fanOut calls fn for every index in [0, n) with at most concurrency calls
+// running at once, and returns when all calls are done
+func fanOut(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
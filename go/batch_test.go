@@ -0,0 +1,129 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendBatchConcurrentSharedOptions(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]int)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[strings.Trim(r.URL.Path, "/")]++
+		mu.Unlock()
+		respondSuccess(w, r)
+	}, WithBeforeSend(func(ctx context.Context, options *NotificationOptions) error {
+		// Writes through the pointer fields race unless every send has its
+		// own copy of them
+		*options.Badge++
+		*options.Volume = 5
+		*options.IsArchive = false
+		options.Actions[0].Label = "changed"
+		return nil
+	}), WithAllowVolumeAllLevels())
+
+	options := NotificationOptions{
+		Body:      "shared",
+		Badge:     Int(1),
+		Volume:    Int(3),
+		IsArchive: Bool(true),
+		Actions:   []Action{{Label: "Open", URL: "https://example.com"}},
+	}
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	results, err := client.SendBatchConcurrent(context.Background(), keys, options, 16)
+	if err != nil {
+		t.Fatalf("SendBatchConcurrent: %v", err)
+	}
+	if summary := results.Summary(); summary.Succeeded != len(keys) {
+		t.Errorf("summary = %+v, want %d successes", summary, len(keys))
+	}
+	for _, key := range keys {
+		if received[key] != 1 {
+			t.Errorf("key %s received %d requests, want 1", key, received[key])
+		}
+	}
+
+	if *options.Badge != 1 || *options.Volume != 3 || !*options.IsArchive || options.Actions[0].Label != "Open" {
+		t.Errorf("options changed by the batch: %v, actions %v", options, options.Actions)
+	}
+}
+
+func TestNotificationOptionsClone(t *testing.T) {
+	original := NotificationOptions{
+		Body:      "body",
+		Badge:     Int(1),
+		Volume:    Int(2),
+		IsArchive: Bool(true),
+		Actions:   []Action{{Label: "Open", URL: "https://example.com"}},
+	}
+
+	clone := original.Clone()
+	*clone.Badge = 10
+	*clone.Volume = 10
+	*clone.IsArchive = false
+	clone.Actions[0].Label = "changed"
+
+	if *original.Badge != 1 {
+		t.Errorf("Badge = %d, want 1", *original.Badge)
+	}
+	if *original.Volume != 2 {
+		t.Errorf("Volume = %d, want 2", *original.Volume)
+	}
+	if !*original.IsArchive {
+		t.Error("IsArchive = false, want true")
+	}
+	if original.Actions[0].Label != "Open" {
+		t.Errorf("Actions[0].Label = %q, want %q", original.Actions[0].Label, "Open")
+	}
+
+	if empty := (NotificationOptions{}).Clone(); empty.Badge != nil || empty.Volume != nil || empty.IsArchive != nil || empty.Actions != nil {
+		t.Errorf("Clone of empty options = %v, want nil fields kept nil", empty)
+	}
+}
+
+func TestSendManyConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		respondSuccess(w, r)
+	})
+
+	bodies := make([]string, 12)
+	for i := range bodies {
+		bodies[i] = fmt.Sprintf("host-%d: ok", i)
+	}
+	results, err := client.SendManyConcurrent(context.Background(), bodies, NotificationOptions{Group: "digest"}, 2)
+	if err != nil {
+		t.Fatalf("SendManyConcurrent: %v", err)
+	}
+	for i, result := range results {
+		if result.Err != nil || result.Body != bodies[i] || result.Key != "testkey" {
+			t.Errorf("result %d = %+v, want a success for %q", i, result, bodies[i])
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("%d sends in flight, want at most 2", maxInFlight)
+	}
+}
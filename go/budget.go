@@ -0,0 +1,62 @@
+package bark
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRetryBudget caps the total time a batch (SendBatch, SendBatchChunked,
+// SendTemplate, DeleteBatch) may spend waiting between retries, summed over
+// all of its keys. Once the budget is used up, failed sends of the batch are
+// no longer retried and are reported with the error of their last attempt,
+// so a few slow keys can't hold up the whole batch during an outage.
+//
+// The budget only limits retries set up with WithRetry; every key still
+// gets its first attempt.
+func WithRetryBudget(total time.Duration) Option {
+	return func(c *Client) {
+		c.retryBudget = total
+	}
+}
+
+// retryBudget is the retry wait time left to a batch, shared by its sends
+type retryBudget struct {
+	mu        sync.Mutex
+	remaining time.Duration
+}
+
+// take reserves d from the budget, reporting whether enough was left
+func (b *retryBudget) take(d time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if d > b.remaining {
+		b.remaining = 0
+		return false
+	}
+	b.remaining -= d
+	return true
+}
+
+// retryBudgetKey is the context key of the batch's retryBudget
+type retryBudgetKey struct{}
+
+// withRetryBudget returns ctx carrying a fresh budget for a batch, unless
+// no budget is configured or ctx already belongs to a batch
+func (c *Client) withRetryBudget(ctx context.Context) context.Context {
+	if c.retryBudget <= 0 || ctx.Value(retryBudgetKey{}) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, retryBudgetKey{}, &retryBudget{remaining: c.retryBudget})
+}
+
+// takeRetryBudget reserves a retry delay from the batch's budget in ctx. It
+// reports true when ctx has no budget.
+func takeRetryBudget(ctx context.Context, d time.Duration) bool {
+	budget, ok := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if !ok {
+		return true
+	}
+	return budget.take(d)
+}
@@ -0,0 +1,158 @@
+package bark
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CaptureTransport is an http.RoundTripper for tests that records every
+// request instead of sending it and answers with programmed responses.
+// Installed in a client's HTTP client, it exercises the real URL building
+// and response parsing without a Bark server:
+//
+//	capture := bark.NewCaptureTransport()
+//	client.SetHTTPClient(&http.Client{Transport: capture})
+//
+// Unless told otherwise with Respond or RespondWith, every request is
+// answered with a successful Bark response. It is safe for concurrent use.
+type CaptureTransport struct {
+	mu       sync.Mutex
+	requests []CapturedRequest
+	respond  func(req CapturedRequest) CaptureResponse
+}
+
+// CapturedRequest is a request recorded by CaptureTransport
+type CapturedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+
+	// Body is the request body, nil for requests without one
+	Body []byte
+}
+
+// CaptureResponse is a canned response returned by CaptureTransport
+type CaptureResponse struct {
+	// StatusCode is the HTTP status code, 200 if zero
+	StatusCode int
+
+	Header http.Header
+	Body   string
+
+	// Err, when set, is returned as a transport error instead of a response
+	Err error
+}
+
+// NewCaptureTransport creates a CaptureTransport answering every request
+// with a successful Bark response
+func NewCaptureTransport() *CaptureTransport {
+	return &CaptureTransport{}
+}
+
+// Respond sets the function that picks the response for each request. It
+// replaces any earlier Respond or RespondWith.
+func (t *CaptureTransport) Respond(fn func(req CapturedRequest) CaptureResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.respond = fn
+}
+
+// RespondWith answers every following request with statusCode and body
+func (t *CaptureTransport) RespondWith(statusCode int, body string) {
+	t.Respond(func(CapturedRequest) CaptureResponse {
+		return CaptureResponse{StatusCode: statusCode, Body: body}
+	})
+}
+
+// Requests returns the requests recorded so far, oldest first
+func (t *CaptureTransport) Requests() []CapturedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]CapturedRequest(nil), t.requests...)
+}
+
+// LastRequest returns the most recent request, false if none was recorded
+func (t *CaptureTransport) LastRequest() (CapturedRequest, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.requests) == 0 {
+		return CapturedRequest{}, false
+	}
+	return t.requests[len(t.requests)-1], true
+}
+
+// Reset forgets the recorded requests. Programmed responses are kept.
+func (t *CaptureTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CaptureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	captured := CapturedRequest{
+		Method: req.Method,
+		URL:    cloneURL(req.URL),
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		captured.Body = body
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, captured)
+	respond := t.respond
+	t.mu.Unlock()
+
+	response := CaptureResponse{
+		Body: fmt.Sprintf(`{"code":200,"message":"success","timestamp":%d}`, time.Now().Unix()),
+	}
+	if respond != nil {
+		response = respond(captured)
+	}
+	if response.Err != nil {
+		return nil, response.Err
+	}
+
+	status := response.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cloneURL returns a copy of u that later changes to u don't affect
+func cloneURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+	clone := *u
+	return &clone
+}
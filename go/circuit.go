@@ -0,0 +1,165 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker set with WithCircuitBreaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the client's circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through (default)
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects requests with ErrCircuitOpen until the cooldown
+	// has passed
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single trial request through, whose outcome
+	// closes or reopens the circuit
+	CircuitHalfOpen
+)
+
+// String returns the name of the state, e.g. for metrics labels
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "CircuitState(unknown)"
+	}
+}
+
+// circuitBreaker tracks consecutive send failures
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+
+	// trial is set while the half-open trial request is in flight
+	trial bool
+}
+
+// WithCircuitBreaker stops sending for cooldown after failureThreshold
+// consecutive requests failed with a transport error, 429 or 5xx (after
+// their retries). While open, requests fail right away with ErrCircuitOpen.
+// After the cooldown a single trial request is let through: if it succeeds
+// the circuit closes, otherwise it opens for another cooldown.
+//
+// Errors the server reports for the notification itself, such as an
+// invalid key, show the server is up and reset the failure count. Failures
+// of the client itself, such as a failed request modifier, don't count.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if failureThreshold > 0 {
+			c.breaker = &circuitBreaker{threshold: failureThreshold, cooldown: cooldown}
+		}
+	}
+}
+
+// CircuitState returns the state of the circuit breaker, CircuitClosed when
+// none is configured
+func (c *Client) CircuitState() CircuitState {
+	b := c.breaker
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current(c.clock.Now())
+}
+
+// current returns the state at now, moving an open circuit whose cooldown
+// has passed to half-open. b.mu must be held.
+func (b *circuitBreaker) current(now time.Time) CircuitState {
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+		b.trial = false
+	}
+	return b.state
+}
+
+// allowRequest reports whether the circuit breaker lets a request through.
+// Every allowed request must be followed by recordOutcome or releaseRequest.
+func (c *Client) allowRequest() bool {
+	b := c.breaker
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.current(c.clock.Now()) {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+	}
+	return true
+}
+
+// recordOutcome updates the circuit breaker with the outcome of a request.
+// Requests ended by their caller's context count neither way.
+func (c *Client) recordOutcome(ctx context.Context, err error) {
+	b := c.breaker
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	halfOpen := b.state == CircuitHalfOpen
+	b.trial = false
+
+	failed := err != nil && isRetryable(err)
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up; a trial request gets another chance
+		return
+	}
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if halfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = c.clock.Now()
+	}
+}
+
+// releaseRequest ends a request let through by allowRequest without counting
+// it either way, for requests that failed before reaching the server, such
+// as when a request modifier failed. A half-open circuit lets the next
+// request through as its trial.
+func (c *Client) releaseRequest() {
+	b := c.breaker
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
@@ -0,0 +1,73 @@
+package bark
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerIgnoresLocalFailures(t *testing.T) {
+	failModifier := true
+	client := newTestClient(t, respondSuccess,
+		WithCircuitBreaker(2, time.Minute),
+		WithRequestModifier(func(req *http.Request) error {
+			if failModifier {
+				return errors.New("no credentials")
+			}
+			return nil
+		}),
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Send(NotificationOptions{Body: "hello"}); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("send %d: err = %v, want the modifier's error", i, err)
+		}
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+
+	failModifier = false
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+		t.Errorf("Send: %v", err)
+	}
+}
+
+func TestCircuitBreakerOpensOnTransportFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(respondSuccess))
+	server.Close()
+
+	client, err := NewClient("testkey", server.URL, WithCircuitBreaker(2, time.Minute))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Send(NotificationOptions{Body: "hello"}); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("send %d: err = %v, want a transport error", i, err)
+		}
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Errorf("state = %s, want open", state)
+	}
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerOpensOnServerErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":503,"message":"busy"}`, http.StatusServiceUnavailable)
+	}, WithCircuitBreaker(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Send(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrServerBusy) {
+			t.Fatalf("send %d: err = %v, want ErrServerBusy", i, err)
+		}
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Errorf("state = %s, want open", state)
+	}
+}
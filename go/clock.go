@@ -0,0 +1,33 @@
+package bark
+
+import "time"
+
+// Clock is the source of time used by the client for latency measurement
+// and waits between attempts. It can be replaced with WithClock to make
+// time-dependent behavior deterministic in tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Sleep pauses the calling goroutine for at least d
+	Sleep(d time.Duration)
+
+	// After waits for d and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock replaces the client's time source, mainly for tests with a fake clock
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package bark
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ClientConfig is the effective configuration of a Client as returned by
+// Config, meant for diagnostics. The key is redacted and hooks and other
+// functions are only reported as enabled.
+type ClientConfig struct {
+	// Key is the redacted Bark key, see WithKeyRedaction
+	Key string `json:"key"`
+
+	ServerURL       string   `json:"server_url"`
+	FailoverServers []string `json:"failover_servers,omitempty"`
+
+	// Timeout is the timeout of the HTTP client, 0 for none
+	Timeout time.Duration `json:"timeout"`
+
+	// SendTimeout bounds each send including retries, see WithContextTimeout
+	SendTimeout time.Duration `json:"send_timeout,omitempty"`
+
+	Retry       RetryPolicy   `json:"retry"`
+	RetryBudget time.Duration `json:"retry_budget,omitempty"`
+
+	SendMode      SendMode `json:"send_mode"`
+	AutoThreshold int      `json:"auto_threshold"`
+
+	SuccessStatusCodes []int  `json:"success_status_codes"`
+	ContentType        string `json:"content_type"`
+	AcceptLanguage     string `json:"accept_language,omitempty"`
+	PathTemplate       string `json:"path_template,omitempty"`
+
+	DefaultLevel string `json:"default_level,omitempty"`
+	DefaultSound string `json:"default_sound,omitempty"`
+	Source       string `json:"source,omitempty"`
+
+	// DefaultArchive is nil when WithDefaultArchive is not set
+	DefaultArchive *bool `json:"default_archive,omitempty"`
+
+	DebugLevel    DebugLevel    `json:"debug_level"`
+	FeatureGating FeatureGating `json:"feature_gating"`
+
+	// HealthCheckInterval and LatencyRoutingInterval are 0 when disabled
+	HealthCheckInterval    time.Duration `json:"health_check_interval,omitempty"`
+	LatencyRoutingInterval time.Duration `json:"latency_routing_interval,omitempty"`
+
+	// CircuitBreakerThreshold is 0 when the circuit breaker is disabled
+	CircuitBreakerThreshold int           `json:"circuit_breaker_threshold,omitempty"`
+	CircuitBreakerCooldown  time.Duration `json:"circuit_breaker_cooldown,omitempty"`
+
+	// QueueCapacity is 0 when the send queue is disabled
+	QueueCapacity int `json:"queue_capacity,omitempty"`
+
+	// MaxConcurrency is 0 when the requests in flight are not limited
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// Features lists the enabled on/off options by name, e.g.
+	// "WithIdempotency", sorted
+	Features []string `json:"features,omitempty"`
+}
+
+// Config returns the client's effective configuration, with every option
+// applied and the key redacted
+func (c *Client) Config() ClientConfig {
+	config := ClientConfig{
+		Key:                    c.redactedKey(c.currentKey()),
+		ServerURL:              c.currentServerURL(),
+		FailoverServers:        append([]string(nil), c.failoverServers...),
+		SendTimeout:            c.sendTimeout,
+		Retry:                  c.retryPolicy,
+		RetryBudget:            c.retryBudget,
+		SendMode:               c.sendMode,
+		AutoThreshold:          c.autoThreshold,
+		SuccessStatusCodes:     append([]int(nil), c.successStatusCodes...),
+		ContentType:            c.postContentType(),
+		AcceptLanguage:         c.acceptLanguage,
+		PathTemplate:           c.pathTemplate,
+		DefaultLevel:           c.defaultLevel,
+		DefaultSound:           c.defaultSound,
+		Source:                 c.source,
+		DebugLevel:             c.debugLevel,
+		FeatureGating:          c.featureGating,
+		HealthCheckInterval:    c.health.interval,
+		LatencyRoutingInterval: c.latency.interval,
+	}
+	if httpClient := c.currentHTTPClient(); httpClient != nil {
+		config.Timeout = httpClient.Timeout
+	}
+	if c.breaker != nil {
+		config.CircuitBreakerThreshold = c.breaker.threshold
+		config.CircuitBreakerCooldown = c.breaker.cooldown
+	}
+	if c.queue != nil {
+		config.QueueCapacity = len(c.queue.items)
+	}
+	config.MaxConcurrency = cap(c.inFlight)
+	if c.defaultArchive != nil {
+		config.DefaultArchive = Bool(*c.defaultArchive)
+	}
+
+	// Sorted by name
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{"WithAfterSend", len(c.afterSend) > 0},
+		{"WithAfterSendResult", len(c.afterSendResult) > 0},
+		{"WithAllowVolumeAllLevels", c.allowVolumeAllLevels},
+		{"WithAutoGroup", c.autoGroup != nil},
+		{"WithBeforeSend", len(c.beforeSend) > 0},
+		{"WithDeadLetter", c.deadLetter != nil},
+		{"WithDisableOnInvalidKey", c.disableOnInvalidKey},
+		{"WithHTTPDoer", c.doer != nil},
+		{"WithFailFast", c.health.failFast},
+		{"WithIdempotency", c.idempotency},
+		{"WithLaxKeyValidation", c.laxKeyValidation},
+		{"WithLogger", c.logger != nil},
+		{"WithMarshaler", c.marshaler != nil},
+		{"WithOfflineVerify", c.offlineVerify},
+		{"WithOnWait", c.onWait != nil},
+		{"WithParamOrder", c.paramOrder != nil},
+		{"WithQueryStyleGET", c.queryStyleGET},
+		{"WithQuietHours", c.quietHours != nil},
+		{"WithRawErrors", c.rawErrors},
+		{"WithRequestModifier", len(c.requestModifiers) > 0},
+		{"WithSanitizeBody", c.sanitizeBody},
+		{"WithStrictArguments", c.strictArguments},
+		{"WithStrictValidation", c.strictValidation},
+		{"WithTestMode", c.testMode},
+		{"WithURLShortener", c.urlShortener != nil},
+		{"WithURLShortenerRequired", c.urlShortenerRequired},
+		{"WithWarningHandler", c.warningHandler != nil},
+		{"WithoutBodyCodeCheck", c.skipBodyCodeCheck},
+		{"WithoutResponseParsing", c.skipResponseParsing},
+	}
+	for _, f := range features {
+		if f.enabled {
+			config.Features = append(config.Features, f.name)
+		}
+	}
+	return config
+}
+
+// SetKey replaces the Bark key used by later sends. It is safe to call while
+// other goroutines are sending, unlike assigning Client.Key directly. The
+// key is trimmed and validated as in NewClient.
+func (c *Client) SetKey(key string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return ErrEmptyKey
+	}
+	if err := c.validateKey(key); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.Key = key
+	c.mu.Unlock()
+	return nil
+}
+
+// SetServerURL replaces the primary server used by later sends. It is safe
+// to call while other goroutines are sending, unlike assigning
+// Client.ServerURL directly. An empty URL selects DefaultServerURL. The
+// cached ServerInfo is dropped, as it described the previous server.
+//
+// unix:// URLs are only supported by NewClient.
+func (c *Client) SetServerURL(serverURL string) {
+	if serverURL == "" {
+		serverURL = DefaultServerURL
+	}
+
+	c.mu.Lock()
+	c.ServerURL = serverURL
+	c.mu.Unlock()
+
+	c.serverInfo.mu.Lock()
+	c.serverInfo.info = nil
+	c.serverInfo.err = nil
+	c.serverInfo.mu.Unlock()
+}
+
+// SetHTTPClient replaces the HTTP client used by later requests. It is safe
+// to call while other goroutines are sending, unlike assigning
+// Client.HTTPClient directly. Requests in flight finish on the previous
+// client.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.mu.Lock()
+	c.HTTPClient = httpClient
+	c.mu.Unlock()
+}
+
+// currentKey returns the Bark key
+func (c *Client) currentKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Key
+}
+
+// currentServerURL returns the primary server URL
+func (c *Client) currentServerURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ServerURL
+}
+
+// currentHTTPClient returns the HTTP client
+func (c *Client) currentHTTPClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.HTTPClient
+}
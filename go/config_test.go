@@ -0,0 +1,49 @@
+package bark
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestReconfigureWhileSending(t *testing.T) {
+	servers := make([]*httptest.Server, 2)
+	for i := range servers {
+		servers[i] = httptest.NewServer(http.HandlerFunc(respondSuccess))
+		defer servers[i].Close()
+	}
+
+	client, err := NewClient("key0", servers[0].URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+					t.Errorf("Send: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		if err := client.SetKey(fmt.Sprintf("key%d", i)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		client.SetServerURL(servers[i%2].URL)
+		client.SetHTTPClient(&http.Client{})
+	}
+	wg.Wait()
+
+	if got := client.Config().ServerURL; got != servers[1].URL {
+		t.Errorf("ServerURL = %s, want %s", got, servers[1].URL)
+	}
+}
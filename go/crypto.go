@@ -0,0 +1,198 @@
+package bark
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Mode is the AES block cipher mode used for encrypted notifications. It
+// must match the mode selected in the Bark app's encryption settings.
+type Mode int
+
+const (
+	// ModeCBC is AES in CBC mode with PKCS7 padding and a 16-byte IV
+	ModeCBC Mode = iota
+
+	// ModeECB is AES in ECB mode with PKCS7 padding and no IV
+	ModeECB
+
+	// ModeGCM is AES in GCM mode with a 12-byte IV and the tag appended to
+	// the ciphertext
+	ModeGCM
+)
+
+var (
+	// ErrInvalidEncryptionKey is returned when the key is not 16, 24 or 32 bytes long
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key. must be 16, 24 or 32 bytes for AES-128, AES-192 or AES-256")
+
+	// ErrInvalidIV is returned when the IV length doesn't match the mode
+	ErrInvalidIV = errors.New("invalid IV length for encryption mode")
+
+	// ErrDecryption is returned when a ciphertext can't be decrypted, because
+	// it was tampered with or encrypted with another key, IV or mode
+	ErrDecryption = errors.New("failed to decrypt ciphertext")
+)
+
+// ivAlphabet is the character set of generated IVs. The Bark app takes the IV
+// as a string, so it is kept printable.
+const ivAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Encrypt encrypts plaintext for the Bark app, returning the base64
+// ciphertext and the randomly generated IV to send along with it (empty for
+// ModeECB). plaintext is usually the JSON-encoded notification content, e.g.
+// {"title":"...","body":"..."}, and key is the key set in the Bark app.
+func Encrypt(plaintext string, key []byte, mode Mode) (ciphertext, iv string, err error) {
+	block, err := newBlockCipher(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	iv, err = randomIV(ivLength(mode))
+	if err != nil {
+		return "", "", err
+	}
+
+	var sealed []byte
+	switch mode {
+	case ModeCBC:
+		data := pkcs7Pad([]byte(plaintext), block.BlockSize())
+		sealed = make([]byte, len(data))
+		cipher.NewCBCEncrypter(block, []byte(iv)).CryptBlocks(sealed, data)
+	case ModeECB:
+		data := pkcs7Pad([]byte(plaintext), block.BlockSize())
+		sealed = make([]byte, len(data))
+		for i := 0; i < len(data); i += block.BlockSize() {
+			block.Encrypt(sealed[i:], data[i:])
+		}
+	case ModeGCM:
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return "", "", err
+		}
+		sealed = gcm.Seal(nil, []byte(iv), []byte(plaintext), nil)
+	default:
+		return "", "", fmt.Errorf("unknown encryption mode %d", mode)
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), iv, nil
+}
+
+// Decrypt reverses Encrypt, returning the plaintext of a base64 ciphertext.
+// Padding and, for ModeGCM, the authentication tag are verified; a
+// ciphertext that fails either check returns ErrDecryption.
+func Decrypt(ciphertext, iv string, key []byte, mode Mode) (string, error) {
+	block, err := newBlockCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != ivLength(mode) {
+		return "", ErrInvalidIV
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base64: %v", ErrDecryption, err)
+	}
+
+	switch mode {
+	case ModeCBC, ModeECB:
+		if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+			return "", fmt.Errorf("%w: ciphertext is not a multiple of the block size", ErrDecryption)
+		}
+		plain := make([]byte, len(data))
+		if mode == ModeCBC {
+			cipher.NewCBCDecrypter(block, []byte(iv)).CryptBlocks(plain, data)
+		} else {
+			for i := 0; i < len(data); i += block.BlockSize() {
+				block.Decrypt(plain[i:], data[i:])
+			}
+		}
+		plain, err = pkcs7Unpad(plain, block.BlockSize())
+		if err != nil {
+			return "", err
+		}
+		return string(plain), nil
+	case ModeGCM:
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return "", err
+		}
+		plain, err := gcm.Open(nil, []byte(iv), data, nil)
+		if err != nil {
+			return "", fmt.Errorf("%w: authentication failed", ErrDecryption)
+		}
+		return string(plain), nil
+	default:
+		return "", fmt.Errorf("unknown encryption mode %d", mode)
+	}
+}
+
+// newBlockCipher creates the AES cipher for key
+func newBlockCipher(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+		return aes.NewCipher(key)
+	default:
+		return nil, ErrInvalidEncryptionKey
+	}
+}
+
+// ivLength returns the IV length in bytes used with mode
+func ivLength(mode Mode) int {
+	switch mode {
+	case ModeECB:
+		return 0
+	case ModeGCM:
+		return 12
+	default:
+		return aes.BlockSize
+	}
+}
+
+// randomIV returns a random printable IV of n characters. Random bytes at or
+// above the largest multiple of len(ivAlphabet) are rejected, so every
+// character of the alphabet is equally likely.
+func randomIV(n int) (string, error) {
+	if n == 0 {
+		return "", nil
+	}
+	limit := 256 - 256%len(ivAlphabet)
+	iv := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(iv) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate IV: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(iv) < n {
+				iv = append(iv, ivAlphabet[int(b)%len(ivAlphabet)])
+			}
+		}
+	}
+	return string(iv), nil
+}
+
+// pkcs7Pad pads data to a multiple of blockSize
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// pkcs7Unpad removes and verifies PKCS7 padding
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, fmt.Errorf("%w: invalid padding", ErrDecryption)
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, fmt.Errorf("%w: invalid padding", ErrDecryption)
+		}
+	}
+	return data[:len(data)-n], nil
+}
@@ -0,0 +1,106 @@
+package bark
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncryptRoundTrip(t *testing.T) {
+	const plaintext = `{"title":"Build","body":"grüße, all 17 jobs passed"}`
+	for _, tt := range []struct {
+		name  string
+		mode  Mode
+		ivLen int
+	}{
+		{"cbc", ModeCBC, 16},
+		{"ecb", ModeECB, 0},
+		{"gcm", ModeGCM, 12},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"0123456789abcdef", "0123456789abcdef01234567", "0123456789abcdef0123456789abcdef"} {
+				ciphertext, iv, err := Encrypt(plaintext, []byte(key), tt.mode)
+				if err != nil {
+					t.Fatalf("Encrypt: %v", err)
+				}
+				if len(iv) != tt.ivLen {
+					t.Errorf("IV %q has length %d, want %d", iv, len(iv), tt.ivLen)
+				}
+				for _, c := range iv {
+					if !strings.ContainsRune(ivAlphabet, c) {
+						t.Errorf("IV %q contains %q, outside the IV alphabet", iv, c)
+					}
+				}
+
+				got, err := Decrypt(ciphertext, iv, []byte(key), tt.mode)
+				if err != nil {
+					t.Fatalf("Decrypt: %v", err)
+				}
+				if got != plaintext {
+					t.Errorf("Decrypt = %q, want %q", got, plaintext)
+				}
+			}
+		})
+	}
+}
+
+func TestDecryptRejects(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	wrongKey := []byte("fedcba9876543210")
+	for _, mode := range []Mode{ModeCBC, ModeECB, ModeGCM} {
+		ciphertext, iv, err := Encrypt("secret message", key, mode)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+
+		// A wrong key can happen to yield valid padding in the block modes,
+		// but never the original plaintext
+		if got, err := Decrypt(ciphertext, iv, wrongKey, mode); err == nil && got == "secret message" {
+			t.Errorf("mode %d: decrypted with the wrong key", mode)
+		} else if mode == ModeGCM && !errors.Is(err, ErrDecryption) {
+			t.Errorf("mode %d: wrong key err = %v, want ErrDecryption", mode, err)
+		}
+
+		tampered := []byte(ciphertext)
+		if tampered[0] == 'A' {
+			tampered[0] = 'B'
+		} else {
+			tampered[0] = 'A'
+		}
+		if got, err := Decrypt(string(tampered), iv, key, mode); err == nil && got == "secret message" {
+			t.Errorf("mode %d: tampered ciphertext decrypted to the original", mode)
+		} else if mode == ModeGCM && !errors.Is(err, ErrDecryption) {
+			t.Errorf("mode %d: tampered err = %v, want ErrDecryption", mode, err)
+		}
+
+		if _, err := Decrypt(ciphertext, iv+"x", key, mode); !errors.Is(err, ErrInvalidIV) {
+			t.Errorf("mode %d: long IV err = %v, want ErrInvalidIV", mode, err)
+		}
+	}
+
+	if _, _, err := Encrypt("secret", []byte("short"), ModeCBC); !errors.Is(err, ErrInvalidEncryptionKey) {
+		t.Errorf("short key err = %v, want ErrInvalidEncryptionKey", err)
+	}
+}
+
+func TestRandomIVUniform(t *testing.T) {
+	counts := make(map[rune]int)
+	const n = 62 * 4000
+	iv, err := randomIV(n)
+	if err != nil {
+		t.Fatalf("randomIV: %v", err)
+	}
+	if len(iv) != n {
+		t.Fatalf("len = %d, want %d", len(iv), n)
+	}
+	for _, c := range iv {
+		counts[c]++
+	}
+	// Each character is expected 4000 times; the modulo bias this guards
+	// against made the first 8 characters about 25% more likely
+	for _, c := range ivAlphabet {
+		if counts[c] < 3600 || counts[c] > 4400 {
+			t.Errorf("%q drawn %d times, want about 4000", c, counts[c])
+		}
+	}
+}
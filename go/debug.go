@@ -0,0 +1,118 @@
+package bark
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DebugLevel sets how much of each request the client logs
+type DebugLevel int
+
+const (
+	// DebugOff disables request logging (default)
+	DebugOff DebugLevel = iota
+
+	// DebugSummary logs the method, server host, status and latency of
+	// every request
+	DebugSummary
+
+	// DebugFull additionally logs the request URL, headers and body, and
+	// the response headers and body
+	DebugFull
+)
+
+// redactedHeaders are headers whose values are never logged
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+}
+
+// WithDebugLogging logs every request made by the client to the client's
+// Logger, see WithLogger. The Bark key is redacted wherever it appears, as
+// are credential headers. Encrypted notifications are logged as sent, so
+// their plaintext never shows up.
+func WithDebugLogging(level DebugLevel) Option {
+	return func(c *Client) {
+		c.debugLevel = level
+	}
+}
+
+// logRequest logs req before it is sent, at DebugFull
+func (c *Client) logRequest(req *http.Request, key string) {
+	if c.debugLevel < DebugFull {
+		return
+	}
+
+	c.logf("bark: > %s %s", req.Method, c.redact(req.URL.String(), key))
+	c.logHeaders(">", req.Header)
+
+	if req.GetBody == nil {
+		return
+	}
+	// Multi-device pushes carry device keys in the body
+	if key == "" {
+		c.logf("bark: > <body omitted>")
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	if data, err := io.ReadAll(body); err == nil && len(data) > 0 {
+		c.logf("bark: > %s", c.redact(string(data), key))
+	}
+}
+
+// logResponse logs the outcome of req. At DebugFull resp's body is replaced
+// with a copy so it can be logged after parsing; the returned function logs
+// it and must be called once the body has been read.
+func (c *Client) logResponse(req *http.Request, key string, resp *http.Response, err error, latency time.Duration) func() {
+	if c.debugLevel < DebugSummary {
+		return func() {}
+	}
+
+	if err != nil {
+		c.logf("bark: %s %s failed after %v: %s", req.Method, req.URL.Host, latency, c.redact(err.Error(), key))
+		return func() {}
+	}
+	c.logf("bark: %s %s: %d in %v", req.Method, req.URL.Host, resp.StatusCode, latency)
+	if c.debugLevel < DebugFull {
+		return func() {}
+	}
+
+	c.logHeaders("<", resp.Header)
+	var body bytes.Buffer
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(resp.Body, &body), resp.Body}
+	return func() {
+		if body.Len() > 0 {
+			c.logf("bark: < %s", c.redact(body.String(), key))
+		}
+	}
+}
+
+// logHeaders logs header sorted by name, prefixed with dir
+func (c *Client) logHeaders(dir string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = "***"
+		}
+		c.logf("bark: %s %s: %s", dir, name, value)
+	}
+}
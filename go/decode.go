@@ -0,0 +1,104 @@
+package bark
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// readBody reads the body of resp as UTF-8 text. Bodies are decompressed
+// when they still carry a gzip Content-Encoding, which net/http only
+// removes for requests it compressed itself, and converted from the charset
+// declared in the Content-Type. ISO-8859-1 and UTF-16 are converted; other
+// charsets are returned as is.
+func readBody(resp *http.Response) ([]byte, error) {
+	var r io.Reader = resp.Body
+	if isGzip(resp.Header.Get("Content-Encoding")) {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return toUTF8(body, resp.Header.Get("Content-Type")), nil
+}
+
+// isGzip reports whether a Content-Encoding header lists gzip
+func isGzip(encoding string) bool {
+	for _, e := range strings.Split(encoding, ",") {
+		if e = strings.TrimSpace(e); strings.EqualFold(e, "gzip") || strings.EqualFold(e, "x-gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// toUTF8 converts body from the charset declared in contentType to UTF-8
+func toUTF8(body []byte, contentType string) []byte {
+	var charset string
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		charset = strings.ToLower(params["charset"])
+	}
+
+	switch charset {
+	case "iso-8859-1", "latin1", "l1":
+		if !hasNonASCII(body) {
+			return body
+		}
+		out := make([]byte, 0, len(body)*2)
+		for _, b := range body {
+			out = utf8.AppendRune(out, rune(b))
+		}
+		return out
+	case "utf-16", "utf-16le", "utf-16be":
+		return utf16ToUTF8(body, charset)
+	default:
+		return bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	}
+}
+
+// hasNonASCII reports whether b contains bytes outside of ASCII
+func hasNonASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// utf16ToUTF8 decodes a UTF-16 body. Plain "utf-16" is big-endian unless
+// a byte order mark says otherwise.
+func utf16ToUTF8(body []byte, charset string) []byte {
+	bigEndian := charset != "utf-16le"
+	if charset == "utf-16" && len(body) >= 2 {
+		switch {
+		case body[0] == 0xff && body[1] == 0xfe:
+			bigEndian, body = false, body[2:]
+		case body[0] == 0xfe && body[1] == 0xff:
+			body = body[2:]
+		}
+	}
+
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+		} else {
+			units[i] = uint16(body[2*i+1])<<8 | uint16(body[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
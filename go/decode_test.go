@@ -0,0 +1,112 @@
+package bark
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// gzipped returns s compressed with gzip
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(gz, s); err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSendGzipResponse(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"success", http.StatusOK, `{"code":200,"message":"success","timestamp":1700000000}`, nil},
+		{"error", http.StatusBadRequest, `{"code":400,"message":"failed to get device token"}`, ErrInvalidKey},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", "gzip")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write(gzipped(t, tt.body))
+			})
+			// Without transparent decompression the gzip body reaches the
+			// client as is, as it does through custom transports
+			client.SetHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}})
+
+			response, err := client.Send(NotificationOptions{Body: "hello"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if response.Message != "success" || response.Timestamp != 1700000000 {
+				t.Errorf("response = %+v, want the decoded success response", response)
+			}
+		})
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		contentType string
+		encoding    string
+		body        []byte
+		want        string
+	}{
+		{"utf-8", "application/json; charset=utf-8", "", []byte(`{"message":"grüße"}`), `{"message":"grüße"}`},
+		{"utf-8 bom", "application/json", "", []byte("\xef\xbb\xbf{}"), `{}`},
+		{"latin1", "application/json; charset=ISO-8859-1", "", []byte("{\"message\":\"gr\xfc\xdfe\"}"), `{"message":"grüße"}`},
+		{"utf-16le bom", "application/json; charset=utf-16", "", []byte{0xff, 0xfe, '{', 0, '}', 0}, `{}`},
+		{"utf-16be", "application/json; charset=UTF-16BE", "", []byte{0, '{', 0, 0xfc, 0, '}'}, "{ü}"},
+		{"unknown charset", "application/json; charset=koi8-r", "", []byte(`{}`), `{}`},
+		{"gzip latin1", "application/json; charset=latin1", "gzip", nil, `{"message":"grüße"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if tt.encoding == "gzip" {
+				body = gzipped(t, "{\"message\":\"gr\xfc\xdfe\"}")
+			}
+			resp := &http.Response{
+				Header: http.Header{"Content-Type": {tt.contentType}},
+				Body:   io.NopCloser(bytes.NewReader(body)),
+			}
+			if tt.encoding != "" {
+				resp.Header.Set("Content-Encoding", tt.encoding)
+			}
+
+			got, err := readBody(resp)
+			if err != nil {
+				t.Fatalf("readBody: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readBody = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadBodyInvalidGzip(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": {"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader([]byte("not gzip"))),
+	}
+	if _, err := readBody(resp); err == nil {
+		t.Error("readBody succeeded, want an invalid gzip error")
+	}
+}
@@ -0,0 +1,70 @@
+package bark
+
+// WithDefaultLevel sets the level used when a send doesn't set one. A level
+// set on the notification always wins. NewClient returns ErrInvalidLevel for
+// an unknown level.
+func WithDefaultLevel(level string) Option {
+	return func(c *Client) {
+		c.defaultLevel = level
+	}
+}
+
+// WithDefaultSound sets the sound used when a send doesn't set one. A sound
+// set on the notification always wins. In strict mode NewClient returns
+// ErrInvalidSound if sound is not one of the Bark app's built-in sounds.
+func WithDefaultSound(sound string) Option {
+	return func(c *Client) {
+		c.defaultSound = sound
+	}
+}
+
+// WithDefaultArchive sets whether notifications that leave IsArchive nil are
+// archived in the Bark app's history. An explicit IsArchive always wins, so
+// with WithDefaultArchive(true) a transient notification can still opt out:
+//
+//	options.IsArchive = bark.Bool(false) // sent as isArchive=0
+func WithDefaultArchive(archive bool) Option {
+	return func(c *Client) {
+		c.defaultArchive = Bool(archive)
+	}
+}
+
+// WithAutoGroup derives the group of notifications that don't set one by
+// calling fn with the notification, after the other defaults are applied.
+// A group set on the notification always wins, and an empty result leaves
+// the notification ungrouped. The derived group is truncated and validated
+// like any other group.
+func WithAutoGroup(fn func(options NotificationOptions) string) Option {
+	return func(c *Client) {
+		c.autoGroup = fn
+	}
+}
+
+// applyDefaults fills the fields left empty in options with the client's defaults
+func (c *Client) applyDefaults(options *NotificationOptions) {
+	if options.Level == "" {
+		options.Level = c.defaultLevel
+	}
+	if options.Sound == "" {
+		options.Sound = c.defaultSound
+	}
+	if options.IsArchive == nil && c.defaultArchive != nil {
+		options.IsArchive = Bool(*c.defaultArchive)
+	}
+	if options.Group == "" && c.autoGroup != nil {
+		options.Group = c.autoGroup(*options)
+	}
+}
+
+// validateDefaults checks the defaults configured with options
+func (c *Client) validateDefaults() error {
+	if c.defaultLevel != "" && !isValidLevel(c.defaultLevel) {
+		return ErrInvalidLevel
+	}
+	if c.defaultSound != "" {
+		if err := c.validateSound(c.defaultSound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
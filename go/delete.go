@@ -0,0 +1,74 @@
+package bark
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// deleteRequest is the POST body asking the server to remove a notification
+type deleteRequest struct {
+	ID     string `json:"id"`
+	Delete string `json:"delete"`
+}
+
+// Delete removes the notification that was sent with the given ID from the
+// device and its history. Requires a Bark server and app that support the
+// "delete" parameter.
+func (c *Client) Delete(ctx context.Context, id string) (*Response, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+
+	ctx, cancel := c.withSendTimeout(ctx)
+	defer cancel()
+
+	key := c.currentKey()
+	result, err := c.execute(ctx, key, func(serverURL string) (*http.Request, error) {
+		return c.newDeleteRequest(ctx, serverURL, key, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Response, nil
+}
+
+// DeleteBatch removes many notifications concurrently, with up to 8 deletes
+// in flight at once. Results are returned in the order of ids, with the ID
+// field of each BatchResult set. An empty ids slice is a no-op.
+func (c *Client) DeleteBatch(ctx context.Context, ids []string) (BatchResults, error) {
+	ctx = c.withRetryBudget(ctx)
+	results := make(BatchResults, len(ids))
+	fanOut(len(ids), defaultBatchConcurrency, func(i int) {
+		results[i].Key = c.currentKey()
+		results[i].ID = ids[i]
+		results[i].Response, results[i].Err = c.Delete(ctx, ids[i])
+	})
+
+	return results, nil
+}
+
+// newDeleteRequest creates a POST request deleting the notification with the given ID
+func (c *Client) newDeleteRequest(ctx context.Context, serverURL, key, id string) (*http.Request, error) {
+	requestURL := serverURL + c.keyPath(key)
+
+	data, err := c.marshal(deleteRequest{ID: id, Delete: "1"})
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to marshal request body: %v", err),
+			Err:     err,
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
+		}
+	}
+	req.Header.Set("Content-Type", c.postContentType())
+
+	return req, nil
+}
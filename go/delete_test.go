@@ -0,0 +1,58 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestDeleteBatch(t *testing.T) {
+	var mu sync.Mutex
+	deleted := make(map[string]int)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params := receivedParams(t, r)
+		if r.Method != http.MethodPost || r.URL.Path != "/testkey" || params["delete"] != "1" {
+			t.Errorf("got %s %s %v, want a POST to /testkey with delete=1", r.Method, r.URL.Path, params)
+		}
+		if params["id"] == "gone" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"message":"notification not found"}`))
+			return
+		}
+		mu.Lock()
+		deleted[params["id"]]++
+		mu.Unlock()
+		respondSuccess(w, r)
+	})
+
+	ids := []string{"deploy-41", "gone", "deploy-42", ""}
+	results, err := client.DeleteBatch(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("%d results, want %d", len(results), len(ids))
+	}
+	for i, result := range results {
+		if result.ID != ids[i] || result.Key != "testkey" {
+			t.Errorf("result %d = %+v, want ID %q and key testkey", i, result, ids[i])
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("results = %v, want deploy-41 and deploy-42 deleted", results)
+	}
+	if !errors.Is(results[1].Err, ErrBadParameters) {
+		t.Errorf("gone: err = %v, want ErrBadParameters", results[1].Err)
+	}
+	if !errors.Is(results[3].Err, ErrEmptyID) {
+		t.Errorf("empty ID: err = %v, want ErrEmptyID", results[3].Err)
+	}
+	if deleted["deploy-41"] != 1 || deleted["deploy-42"] != 1 || len(deleted) != 2 {
+		t.Errorf("deleted %v, want deploy-41 and deploy-42 once each", deleted)
+	}
+	if summary := results.Summary(); summary.Succeeded != 2 || summary.Failed != 2 {
+		t.Errorf("summary = %+v, want 2 succeeded and 2 failed", summary)
+	}
+}
@@ -0,0 +1,63 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+)
+
+// EncodeGET returns the URL Send would request for options, without sending
+// it. The options go through the same preparation as in Send: the source
+// tag, client defaults and validation, quiet hours, feature gating, URL
+// shortening and actions. Like Send, this may fetch the server info or call
+// the URL shortener when those are configured. A notification suppressed
+// by quiet hours fails with ErrSuppressed. The result can be compared
+// against golden files.
+func (c *Client) EncodeGET(options NotificationOptions) (string, error) {
+	key := c.currentKey()
+	options, err := c.prepareEncode(key, options, SendModeGET)
+	if err != nil {
+		return "", err
+	}
+	return c.getURL(c.currentServerURL(), key, options)
+}
+
+// EncodePOST returns the JSON body SendPost would send for options, without
+// sending it. The options are prepared as by EncodeGET.
+func (c *Client) EncodePOST(options NotificationOptions) ([]byte, error) {
+	options, err := c.prepareEncode(c.currentKey(), options, SendModePOST)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.marshal(options)
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to marshal request body: %v", err),
+			Err:     err,
+		}
+	}
+	return data, nil
+}
+
+// prepareEncode prepares options for the given send mode as a send would
+func (c *Client) prepareEncode(key string, options NotificationOptions, mode SendMode) (NotificationOptions, error) {
+	options, _, suppress, err := c.prepareSend(context.Background(), key, options, mode)
+	if err != nil {
+		return options, err
+	}
+	if suppress {
+		return options, ErrSuppressed
+	}
+	return options, nil
+}
+
+// EstimateURLLength returns the length of the URL Send would request for
+// options, escaping included, without making a request. Compare it against
+// DefaultAutoThreshold or a proxy's limit to decide between GET and POST.
+func (c *Client) EstimateURLLength(options NotificationOptions) (int, error) {
+	requestURL, err := c.EncodeGET(options)
+	if err != nil {
+		return 0, err
+	}
+	return len(requestURL), nil
+}
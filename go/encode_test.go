@@ -0,0 +1,102 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCaptureClient returns a client for the key "testkey" sending to
+// https://bark.test through a CaptureTransport
+func newCaptureClient(t *testing.T, opts ...Option) (*Client, *CaptureTransport) {
+	t.Helper()
+	client, err := NewClient("testkey", "https://bark.test", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	capture := NewCaptureTransport()
+	client.SetHTTPClient(&http.Client{Transport: capture})
+	return client, capture
+}
+
+func TestEncodeMatchesSend(t *testing.T) {
+	client, capture := newCaptureClient(t,
+		WithSource("web-1"),
+		WithDefaultSound("bell"),
+		WithURLShortener(func(ctx context.Context, long string) (string, error) {
+			return "https://sho.rt/1", nil
+		}),
+	)
+
+	for _, options := range []NotificationOptions{
+		{Body: "disk full"},
+		{Title: "Alert", Body: "disk full", URL: "https://example.com/a/very/long/url"},
+		{Body: "menu", Actions: []Action{{Label: "Open", URL: "https://example.com"}}},
+	} {
+		capture.Reset()
+		wantURL, err := client.EncodeGET(options)
+		if err != nil {
+			t.Fatalf("EncodeGET(%v): %v", options, err)
+		}
+		if _, err := client.Send(options); err != nil {
+			t.Fatalf("Send(%v): %v", options, err)
+		}
+		req, _ := capture.LastRequest()
+		if got := req.URL.String(); got != wantURL {
+			t.Errorf("Send(%v) requested %s, EncodeGET returned %s", options, got, wantURL)
+		}
+
+		wantBody, err := client.EncodePOST(options)
+		if err != nil {
+			t.Fatalf("EncodePOST(%v): %v", options, err)
+		}
+		if _, err := client.SendPost(options); err != nil {
+			t.Fatalf("SendPost(%v): %v", options, err)
+		}
+		req, _ = capture.LastRequest()
+		if string(req.Body) != string(wantBody) {
+			t.Errorf("SendPost(%v) sent %s, EncodePOST returned %s", options, req.Body, wantBody)
+		}
+	}
+
+	// The source and shortened URL are part of the encoded payload
+	body, err := client.EncodePOST(NotificationOptions{Title: "Alert", Body: "disk full", URL: "https://example.com/x"})
+	if err != nil {
+		t.Fatalf("EncodePOST: %v", err)
+	}
+	for _, want := range []string{`"subtitle":"web-1"`, `"sound":"bell"`, `"url":"https://sho.rt/1"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("EncodePOST = %s, want it to contain %s", body, want)
+		}
+	}
+}
+
+func TestEncodeSuppressed(t *testing.T) {
+	client, capture := newCaptureClient(t, WithQuietHours(0, 24*time.Hour-time.Second, time.UTC, QuietHoursSuppress), WithClock(fixedClock{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}))
+
+	if _, err := client.EncodeGET(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrSuppressed) {
+		t.Errorf("EncodeGET: err = %v, want ErrSuppressed", err)
+	}
+	if _, err := client.EncodePOST(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrSuppressed) {
+		t.Errorf("EncodePOST: err = %v, want ErrSuppressed", err)
+	}
+	if len(capture.Requests()) != 0 {
+		t.Errorf("requests = %v, want none", capture.Requests())
+	}
+}
+
+// fixedClock is a Clock that always returns the same time and never waits
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time        { return c.now }
+func (c fixedClock) Sleep(d time.Duration) {}
+func (c fixedClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
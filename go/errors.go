@@ -0,0 +1,169 @@
+package bark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Sentinel errors for failures reported by the Bark server. A BarkError
+// matches one of them via errors.Is when the response code maps to it.
+var (
+	// ErrBadParameters is returned when the server rejects the request
+	// parameters (code 400)
+	ErrBadParameters = errors.New("bad request parameters")
+
+	// ErrInvalidKey is returned when the server doesn't know the Bark key
+	// (code 400 with a device token lookup failure)
+	ErrInvalidKey = errors.New("invalid bark key")
+
+	// ErrPushFailed is returned when the server failed to deliver the push
+	// to Apple's push service (code 500)
+	ErrPushFailed = errors.New("push failed")
+
+	// ErrServerBusy is returned when the server is overloaded or throttling
+	// (codes 429 and 503)
+	ErrServerBusy = errors.New("server busy")
+)
+
+// ErrDNS is returned when the server hostname can't be resolved. This usually
+// means the server URL is misconfigured, so such failures are not retried
+// unless the resolver reports them as temporary. The *net.DNSError remains
+// available through errors.As.
+var ErrDNS = errors.New("server hostname could not be resolved")
+
+// ErrProxyChallenge is returned when a proxy or CDN in front of the server,
+// such as Cloudflare, answered with an HTML challenge page instead of the
+// Bark API. The page is available in BarkError.Body.
+var ErrProxyChallenge = errors.New("proxy challenge instead of bark response")
+
+// maxCapturedBody is the number of response body bytes kept in BarkError.Body
+const maxCapturedBody = 4 << 10
+
+// capturedBody returns a copy of at most maxCapturedBody bytes of body
+func capturedBody(body []byte) []byte {
+	if len(body) > maxCapturedBody {
+		body = body[:maxCapturedBody]
+	}
+	return append([]byte(nil), body...)
+}
+
+// isProxyChallenge reports whether a response that isn't Bark's JSON comes
+// from a proxy challenge: Cloudflare marks its challenges with a
+// "cf-mitigated: challenge" header, other proxies answer a successful status
+// with an HTML page
+func isProxyChallenge(resp *http.Response, body []byte) bool {
+	if strings.EqualFold(resp.Header.Get("cf-mitigated"), "challenge") {
+		return true
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false
+	}
+	if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "text/html") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// newTransportError wraps an error returned by the HTTP client for a request
+// that got no response
+func newTransportError(err error) *BarkError {
+	barkErr := &BarkError{
+		Message:   fmt.Sprintf("request failed: %v", err),
+		Err:       err,
+		transport: true,
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		barkErr.Message = fmt.Sprintf("request failed: check the server URL: %v", err)
+		barkErr.Kind = ErrDNS
+	}
+	return barkErr
+}
+
+var (
+	errorCodesMu sync.RWMutex
+
+	// errorCodes maps server response codes to sentinel errors
+	errorCodes = map[int]error{
+		http.StatusBadRequest:          ErrBadParameters,
+		http.StatusTooManyRequests:     ErrServerBusy,
+		http.StatusInternalServerError: ErrPushFailed,
+		http.StatusServiceUnavailable:  ErrServerBusy,
+	}
+)
+
+// RegisterErrorCode maps a server response code to err, so that a BarkError
+// carrying that code matches err via errors.Is. It replaces any existing
+// mapping for the code, which allows adapting to servers with other codes.
+//
+// The default mapping is:
+//
+//	400 ErrBadParameters (ErrInvalidKey when the key is unknown)
+//	429 ErrServerBusy
+//	500 ErrPushFailed
+//	503 ErrServerBusy
+func RegisterErrorCode(code int, err error) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+
+	if err == nil {
+		delete(errorCodes, code)
+		return
+	}
+	errorCodes[code] = err
+}
+
+// errorForCode returns the sentinel error for a server response code and
+// message, or nil when the code is unknown
+func errorForCode(code int, message string) error {
+	// The server reports an unknown key as a bad request, recognisable
+	// only by its message
+	if code == http.StatusBadRequest && strings.Contains(strings.ToLower(message), "device token") {
+		return ErrInvalidKey
+	}
+
+	errorCodesMu.RLock()
+	defer errorCodesMu.RUnlock()
+	return errorCodes[code]
+}
+
+// WithRawErrors makes sends return the underlying error, such as the
+// *url.Error of a failed request or a JSON decoding error, instead of a
+// *BarkError for failures that aren't reported by the server. Failures the
+// server reports still return *BarkError.
+//
+// Without this option the underlying error is still reachable from the
+// *BarkError with errors.As and errors.Unwrap. The key in the URL of a raw
+// *url.Error is redacted in both cases.
+func WithRawErrors() Option {
+	return func(c *Client) {
+		c.rawErrors = true
+	}
+}
+
+// surfaceError prepares an error to be returned to the caller: the key is
+// redacted from an underlying *url.Error and, with WithRawErrors, the
+// underlying error replaces the *BarkError wrapping it
+func (c *Client) surfaceError(err error, key string) error {
+	var barkErr *BarkError
+	if !errors.As(err, &barkErr) || barkErr.Err == nil {
+		return err
+	}
+
+	var urlErr *url.Error
+	if errors.As(barkErr.Err, &urlErr) {
+		urlErr.URL = c.redact(urlErr.URL, key)
+	}
+
+	if c.rawErrors {
+		return barkErr.Err
+	}
+	return err
+}
@@ -1,5 +1,7 @@
 module github.com/okx_brc20_app/3rdparty/notification/bark/go/example
 
-go 1.19
+go 1.20
 
-replace github.com/okx_brc20_app/3rdparty/notification/bark/go => ../ 
\ No newline at end of file
+replace github.com/okx_brc20_app/3rdparty/notification/bark/go => ../ 
+
+require github.com/okx_brc20_app/3rdparty/notification/bark/go v0.0.0-00010101000000-000000000000
\ No newline at end of file
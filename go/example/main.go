@@ -2,6 +2,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,22 +23,22 @@ func main() {
 		Body: "Hello from Go SDK!",
 	})
 	if err != nil {
-		// Check specific error types
-		switch err {
-		case bark.ErrEmptyBody:
+		// Check specific error types. Errors may be wrapped, so match them
+		// with errors.Is and errors.As rather than == or type assertions
+		var barkErr *bark.BarkError
+		switch {
+		case errors.Is(err, bark.ErrEmptyBody):
 			fmt.Println("Error: Notification body cannot be empty")
-		case bark.ErrInvalidLevel:
+		case errors.Is(err, bark.ErrInvalidLevel):
 			fmt.Println("Error: Invalid notification level")
+		case errors.As(err, &barkErr):
+			fmt.Printf("Bark error: %s (Status code: %d)\n", barkErr.Message, barkErr.StatusCode)
+			if barkErr.Response != nil {
+				fmt.Printf("Response: %+v\n", barkErr.Response)
+			}
 		default:
 			// Handle other errors
-			if barkErr, ok := err.(*bark.BarkError); ok {
-				fmt.Printf("Bark error: %s (Status code: %d)\n", barkErr.Message, barkErr.StatusCode)
-				if barkErr.Response != nil {
-					fmt.Printf("Response: %+v\n", barkErr.Response)
-				}
-			} else {
-				fmt.Printf("Error: %v\n", err)
-			}
+			fmt.Printf("Error: %v\n", err)
 		}
 	} else {
 		fmt.Printf("Response: %+v\n\n", response)
@@ -70,7 +71,7 @@ func main() {
 		Group:     "go-examples",
 		Sound:     "minuet",
 		Level:     bark.LevelTimeSensitive,
-		IsArchive: true,
+		IsArchive: bark.Bool(true),
 		Copy:      "Text to copy",
 	})
 	if err != nil {
@@ -0,0 +1,125 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WithFailoverServers sets Bark servers to fall back to, in order, when a
+// send to the primary server fails with a transport error, 429 or 5xx, or
+// an API code listed in the retry policy's RetryCodes. Each server gets the
+// full retry policy before the next one is tried. Errors the server reports
+// for the notification itself, such as an invalid key, are returned without
+// trying further servers.
+func WithFailoverServers(serverURLs ...string) Option {
+	return func(c *Client) {
+		c.failoverServers = append([]string(nil), serverURLs...)
+	}
+}
+
+// WithFailoverBackoff sets the delay before moving on to the next failover
+// server. Backoff.Next is called with the number of servers tried so far.
+// By default the next server is tried right away.
+func WithFailoverBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.failoverBackoff = backoff
+	}
+}
+
+// ServerAttempt is the outcome of sending to one server during failover
+type ServerAttempt struct {
+	// Server is the host of the server, e.g. "api.day.app"
+	Server string
+
+	// StatusCode is the HTTP status code of the last response, 0 if the
+	// server never answered
+	StatusCode int
+
+	// Err is the error of the last attempt on this server
+	Err error
+}
+
+// FailoverError is returned when a send failed on every server it was tried
+// on. errors.Is and errors.As match against each server's error.
+type FailoverError struct {
+	// Attempts holds one record per server tried, in order
+	Attempts []ServerAttempt
+}
+
+// Error implements the error interface
+func (e *FailoverError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "all %d servers failed", len(e.Attempts))
+	for _, attempt := range e.Attempts {
+		fmt.Fprintf(&b, "; %s: %v", attempt.Server, attempt.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of every server tried
+func (e *FailoverError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		errs[i] = attempt.Err
+	}
+	return errs
+}
+
+// servers returns the primary server followed by the failover servers
+func (c *Client) servers() []string {
+	return append([]string{c.currentServerURL()}, c.failoverServers...)
+}
+
+// sendServers returns the servers to try for a send, in order: the server
+// given to SendTo followed by the failover servers, or the client's servers
+// as ordered by latency routing, without the primary server while it is
+// unhealthy and WithFailFast is set
+func (c *Client) sendServers(ctx context.Context) ([]string, error) {
+	if serverURL, ok := serverOverride(ctx); ok {
+		return append([]string{serverURL}, c.failoverServers...), nil
+	}
+
+	servers := c.routedServers()
+	if c.health.failFast && !c.Healthy() {
+		if servers = withoutServer(servers, c.currentServerURL()); len(servers) == 0 {
+			return nil, ErrServerUnhealthy
+		}
+	}
+	return servers, nil
+}
+
+// serverOverrideKey is the context key under which SendTo passes its server
+type serverOverrideKey struct{}
+
+// serverOverride returns the server given to SendTo for the send of ctx
+func serverOverride(ctx context.Context) (string, bool) {
+	serverURL, ok := ctx.Value(serverOverrideKey{}).(string)
+	return serverURL, ok
+}
+
+// withoutServer returns servers without serverURL
+func withoutServer(servers []string, serverURL string) []string {
+	kept := servers[:0:0]
+	for _, s := range servers {
+		if s != serverURL {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// newServerAttempt records the outcome of sending to serverURL
+func newServerAttempt(serverURL string, err error) ServerAttempt {
+	attempt := ServerAttempt{Server: serverURL, Err: err}
+	if u, parseErr := url.Parse(serverURL); parseErr == nil && u.Host != "" {
+		attempt.Server = u.Host
+	}
+	var barkErr *BarkError
+	if errors.As(err, &barkErr) {
+		attempt.StatusCode = barkErr.StatusCode
+	}
+	return attempt
+}
@@ -0,0 +1,89 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFailover(t *testing.T) {
+	newServer := func(status *atomic.Int32, requests *atomic.Int32) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			switch status.Load() {
+			case http.StatusOK:
+				respondSuccess(w, r)
+			case http.StatusBadRequest:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":400,"message":"failed to get device token"}`))
+			default:
+				http.Error(w, "unavailable", int(status.Load()))
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	var primaryStatus, backupStatus, primaryRequests, backupRequests atomic.Int32
+	primary := newServer(&primaryStatus, &primaryRequests)
+	backup := newServer(&backupStatus, &backupRequests)
+	client, err := NewClient("testkey", primary.URL, WithFailoverServers(backup.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	send := func() (*SendResult, error) {
+		primaryRequests.Store(0)
+		backupRequests.Store(0)
+		return client.SendWithResult(context.Background(), NotificationOptions{Body: "hello"})
+	}
+
+	t.Run("primary down", func(t *testing.T) {
+		primaryStatus.Store(http.StatusServiceUnavailable)
+		backupStatus.Store(http.StatusOK)
+		result, err := send()
+		if err != nil {
+			t.Fatalf("SendWithResult: %v", err)
+		}
+		if result.ServerURL != backup.URL {
+			t.Errorf("ServerURL = %q, want the backup %q", result.ServerURL, backup.URL)
+		}
+		if primaryRequests.Load() != 1 || backupRequests.Load() != 1 {
+			t.Errorf("requests primary/backup = %d/%d, want 1/1", primaryRequests.Load(), backupRequests.Load())
+		}
+	})
+
+	t.Run("notification rejected", func(t *testing.T) {
+		primaryStatus.Store(http.StatusBadRequest)
+		if _, err := send(); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("err = %v, want ErrInvalidKey", err)
+		}
+		if backupRequests.Load() != 0 {
+			t.Errorf("%d requests to the backup, want none for an error about the notification", backupRequests.Load())
+		}
+	})
+
+	t.Run("all down", func(t *testing.T) {
+		primaryStatus.Store(http.StatusServiceUnavailable)
+		backupStatus.Store(http.StatusBadGateway)
+		_, err := send()
+		var failover *FailoverError
+		if !errors.As(err, &failover) {
+			t.Fatalf("err = %v, want a *FailoverError", err)
+		}
+		if len(failover.Attempts) != 2 {
+			t.Fatalf("Attempts = %+v, want one per server", failover.Attempts)
+		}
+		for i, want := range []int{http.StatusServiceUnavailable, http.StatusBadGateway} {
+			if failover.Attempts[i].StatusCode != want {
+				t.Errorf("attempt %d: StatusCode = %d, want %d", i, failover.Attempts[i].StatusCode, want)
+			}
+		}
+		if !errors.Is(err, ErrServerBusy) {
+			t.Errorf("err = %v, want it to match the primary's ErrServerBusy", err)
+		}
+	})
+}
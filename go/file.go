@@ -0,0 +1,25 @@
+package bark
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewClientFromFile creates a client with the Bark key read from the file at
+// path, such as a mounted Docker or Kubernetes secret. Surrounding
+// whitespace is trimmed from the key. The server defaults to
+// DefaultServerURL and can be changed with WithServerURL.
+func NewClientFromFile(path string, opts ...Option) (*Client, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bark key file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return nil, fmt.Errorf("bark key file %s is empty: %w", path, ErrEmptyKey)
+	}
+
+	return NewClient(key, "", opts...)
+}
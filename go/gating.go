@@ -0,0 +1,121 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrUnsupportedFeature is returned in FeatureGatingStrict mode when a
+// notification uses a parameter the server is too old to support
+var ErrUnsupportedFeature = errors.New("feature not supported by server")
+
+// FeatureGating controls what happens to parameters the server doesn't support
+type FeatureGating int
+
+const (
+	// FeatureGatingOff sends every parameter as is (default)
+	FeatureGatingOff FeatureGating = iota
+
+	// FeatureGatingDrop removes unsupported parameters and logs a warning
+	FeatureGatingDrop
+
+	// FeatureGatingStrict fails the send with ErrUnsupportedFeature
+	FeatureGatingStrict
+)
+
+// Logger receives the client's warnings and debug logs. *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// WithLogger sets the logger for the client's warnings and debug logs. By
+// default they go to the standard library's log package.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithFeatureGating checks notifications against the server version before
+// sending them. The version is probed once via ServerInfo and cached. If it
+// can't be determined, notifications are sent unchanged.
+func WithFeatureGating(mode FeatureGating) Option {
+	return func(c *Client) {
+		c.featureGating = mode
+	}
+}
+
+// feature is a notification parameter that requires a minimum server version
+type feature struct {
+	name       string
+	minVersion string
+	used       func(options *NotificationOptions) bool
+	drop       func(options *NotificationOptions)
+}
+
+// gatedFeatures are the parameters older servers reject or silently ignore
+var gatedFeatures = []feature{
+	{
+		name:       paramIcon,
+		minVersion: "v2.0.0",
+		used:       func(o *NotificationOptions) bool { return o.Icon != "" },
+		drop:       func(o *NotificationOptions) { o.Icon = "" },
+	},
+	{
+		name:       "level=critical",
+		minVersion: "v2.1.5",
+		used:       func(o *NotificationOptions) bool { return o.Level == LevelCritical },
+		drop:       func(o *NotificationOptions) { o.Level = "" },
+	},
+	{
+		name:       paramID,
+		minVersion: "v2.2.0",
+		used:       func(o *NotificationOptions) bool { return o.ID != "" },
+		drop:       func(o *NotificationOptions) { o.ID = "" },
+	},
+}
+
+// gateFeatures applies the client's FeatureGating mode to options
+func (c *Client) gateFeatures(ctx context.Context, options NotificationOptions) (NotificationOptions, error) {
+	// The cached version is the primary server's, which says nothing about
+	// a server given to SendTo
+	if _, ok := serverOverride(ctx); ok || c.featureGating == FeatureGatingOff {
+		return options, nil
+	}
+
+	var info *ServerInfo
+	for _, f := range gatedFeatures {
+		if !f.used(&options) {
+			continue
+		}
+		if info == nil {
+			var err error
+			if info, err = c.cachedServerInfo(ctx); err != nil {
+				c.warnf(WarningCheckSkipped, "feature gating skipped, server version unknown: %v", err)
+				return options, nil
+			}
+		}
+		if info.AtLeast(f.minVersion) {
+			continue
+		}
+
+		if c.featureGating == FeatureGatingStrict {
+			return options, fmt.Errorf("%w: %s requires %s, server is %q", ErrUnsupportedFeature, f.name, f.minVersion, info.Version)
+		}
+		c.warnf(WarningFeatureDropped, "dropping %s, requires server %s, server is %q", f.name, f.minVersion, info.Version)
+		f.drop(&options)
+	}
+	return options, nil
+}
+
+// logf writes a warning to the client's logger
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
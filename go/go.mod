@@ -1,3 +1,3 @@
 module github.com/okx_brc20_app/3rdparty/notification/bark/go
 
-go 1.19 
\ No newline at end of file
+go 1.20
\ No newline at end of file
@@ -0,0 +1,90 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrServerUnhealthy is returned when WithFailFast is set and the last health
+// check of the server failed
+var ErrServerUnhealthy = errors.New("server is unhealthy")
+
+// healthCheck is the state of the background health check
+type healthCheck struct {
+	interval  time.Duration
+	failFast  bool
+	unhealthy atomic.Bool
+
+	// cancel stops the checker, done is closed once it has exited
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// WithHealthCheck pings the server every interval in the background and
+// records the outcome, reported by Healthy. The checker runs from NewClient
+// until Shutdown or Close. Combine it with WithFailFast to stop sending to a
+// server that is down.
+func WithHealthCheck(interval time.Duration) Option {
+	return func(c *Client) {
+		c.health.interval = interval
+	}
+}
+
+// WithFailFast makes requests fail right away with ErrServerUnhealthy while
+// the last health check failed, instead of waiting for them to time out.
+// With failover servers configured, requests go straight to those instead.
+// It has no effect without WithHealthCheck.
+func WithFailFast() Option {
+	return func(c *Client) {
+		c.health.failFast = true
+	}
+}
+
+// Healthy reports whether the last health check succeeded. It is true before
+// the first check and when health checks are not enabled.
+func (c *Client) Healthy() bool {
+	return !c.health.unhealthy.Load()
+}
+
+// startHealthCheck starts the background checker if one is configured
+func (c *Client) startHealthCheck() {
+	if c.health.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.health.cancel = cancel
+	c.health.done = make(chan struct{})
+	go c.runHealthCheck(ctx)
+}
+
+// runHealthCheck pings the server every interval until ctx is cancelled.
+// Each ping may take up to one interval.
+func (c *Client) runHealthCheck(ctx context.Context) {
+	defer close(c.health.done)
+
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, c.health.interval)
+		err := c.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		c.health.unhealthy.Store(err != nil)
+
+		if c.sleep(ctx, c.health.interval) != nil {
+			return
+		}
+	}
+}
+
+// stopHealthCheck stops the background checker and waits for it to exit
+func (c *Client) stopHealthCheck() {
+	if c.health.cancel == nil {
+		return
+	}
+	c.health.cancel()
+	<-c.health.done
+}
@@ -0,0 +1,57 @@
+package bark
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	var down atomic.Bool
+	var sends atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/ping" {
+			sends.Add(1)
+		}
+		respondSuccess(w, r)
+	}, WithHealthCheck(5*time.Millisecond), WithFailFast())
+	t.Cleanup(func() { _ = client.Close() })
+
+	waitFor(t, "a passing health check", client.Healthy)
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("Send to a healthy server: %v", err)
+	}
+
+	down.Store(true)
+	waitFor(t, "a failing health check", func() bool { return !client.Healthy() })
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrServerUnhealthy) {
+		t.Errorf("Send to an unhealthy server: err = %v, want ErrServerUnhealthy", err)
+	}
+
+	down.Store(false)
+	waitFor(t, "the server to recover", client.Healthy)
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("Send after recovery: %v", err)
+	}
+	if n := sends.Load(); n != 2 {
+		t.Errorf("%d notifications reached the server, want 2", n)
+	}
+}
@@ -0,0 +1,87 @@
+package bark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ErrorGroup is the group FromError puts error notifications in
+	ErrorGroup = "errors"
+
+	// maxErrorBodyLength is the number of characters of an error message
+	// FromError keeps in the body
+	maxErrorBodyLength = 1024
+)
+
+// FromError builds a critical notification reporting err, ready to send:
+//
+//	client.Send(bark.FromError(err, "Job failed"))
+//
+// The body is err.Error(), truncated to 1024 characters, and the
+// notification is put in the ErrorGroup group.
+func FromError(err error, title string) NotificationOptions {
+	body := "unknown error"
+	if err != nil {
+		body = err.Error()
+	}
+
+	return NotificationOptions{
+		Title: title,
+		Body:  truncate(body, maxErrorBodyLength),
+		Level: LevelCritical,
+		Group: ErrorGroup,
+	}
+}
+
+// Alertf formats the body like fmt.Sprintf and sends it with the given
+// level, for one-line alerts:
+//
+//	client.Alertf(ctx, bark.LevelTimeSensitive, "disk %s at %d%%", disk, usage)
+//
+// An empty level leaves it to the client's default. The notification is
+// sent with SendModeAuto, so long or multi-line bodies go over POST.
+func (c *Client) Alertf(ctx context.Context, level string, format string, args ...interface{}) (*Response, error) {
+	options := NotificationOptions{
+		Body:  fmt.Sprintf(format, args...),
+		Level: level,
+	}
+	result, err := c.send(ctx, c.currentKey(), options, SendModeAuto)
+	if err != nil {
+		return nil, err
+	}
+	return result.Response, nil
+}
+
+// SendJSON sends payload encoded as JSON as the notification body, for
+// automations on the phone that parse it. The title and body of options are
+// replaced; the other fields are sent as given. The notification is sent
+// with SendModeAuto, so payloads too long for a GET URL go over POST.
+//
+// A payload that can't be encoded fails before anything is sent.
+func (c *Client) SendJSON(ctx context.Context, title string, payload interface{}, options NotificationOptions) (*SendResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to marshal payload: %v", err),
+			Err:     err,
+		}
+	}
+
+	options.Title = title
+	options.Body = string(body)
+	return c.send(ctx, c.currentKey(), options, SendModeAuto)
+}
+
+// Int returns a pointer to v, for optional fields such as Badge:
+//
+//	client.Send(bark.NotificationOptions{Body: "3 unread", Badge: bark.Int(3)})
+func Int(v int) *int {
+	return &v
+}
+
+// Bool returns a pointer to v, for optional fields such as IsArchive
+func Bool(v bool) *bool {
+	return &v
+}
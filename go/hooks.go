@@ -0,0 +1,133 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// BeforeSendHook is called before every notification send. It may modify the
+// options; returning an error aborts the send with that error.
+type BeforeSendHook func(ctx context.Context, options *NotificationOptions) error
+
+// AfterSendHook is called after every notification send with its outcome
+type AfterSendHook func(ctx context.Context, response *Response, err error)
+
+// AfterSendResultHook is called after every notification send with its
+// SendResult, which is nil when no request was attempted. It sees what
+// AfterSendHook doesn't, such as the Transport and AttemptCount, e.g. for
+// metrics.
+type AfterSendResultHook func(ctx context.Context, result *SendResult, err error)
+
+// WithBeforeSend adds a hook run before each send, ahead of validation.
+// Hooks run in the order they were added.
+func WithBeforeSend(hook BeforeSendHook) Option {
+	return func(c *Client) {
+		if hook != nil {
+			c.beforeSend = append(c.beforeSend, hook)
+		}
+	}
+}
+
+// WithAfterSend adds a hook run after each send that got past the before
+// hooks, whether it succeeded or not. Hooks run in the order they were added.
+func WithAfterSend(hook AfterSendHook) Option {
+	return func(c *Client) {
+		if hook != nil {
+			c.afterSend = append(c.afterSend, hook)
+		}
+	}
+}
+
+// WithAfterSendResult adds a hook run after each send like WithAfterSend,
+// but passed the send's SendResult. Hooks run in the order they were added,
+// after those of WithAfterSend.
+func WithAfterSendResult(hook AfterSendResultHook) Option {
+	return func(c *Client) {
+		if hook != nil {
+			c.afterSendResult = append(c.afterSendResult, hook)
+		}
+	}
+}
+
+// RequestModifier is called with every request a send makes, after the
+// client has built it and just before it is sent. It may change the request
+// in place; returning an error aborts the send.
+type RequestModifier func(req *http.Request) error
+
+// WithRequestModifier adds a function that adjusts outgoing requests in ways
+// the options don't cover, such as cookies or conditional headers. It runs
+// for every attempt of every send, Delete and SendMulti included, and for
+// the requests of Ping, ServerInfo and the background health and latency
+// checks, in the order modifiers were added. An error aborts the send
+// without retrying.
+func WithRequestModifier(modify RequestModifier) Option {
+	return func(c *Client) {
+		if modify != nil {
+			c.requestModifiers = append(c.requestModifiers, modify)
+		}
+	}
+}
+
+// modifyRequest runs the request modifiers in order, stopping at the first error
+func (c *Client) modifyRequest(req *http.Request) error {
+	for _, modify := range c.requestModifiers {
+		if err := modify(req); err != nil {
+			return &BarkError{
+				Message: fmt.Sprintf("request modifier failed: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// runBeforeSend runs the before hooks in order, stopping at the first error
+func (c *Client) runBeforeSend(ctx context.Context, options *NotificationOptions) error {
+	for _, hook := range c.beforeSend {
+		if err := hook(ctx, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfterSend runs the after hooks in order
+func (c *Client) runAfterSend(ctx context.Context, result *SendResult, err error) {
+	var response *Response
+	if result != nil {
+		response = result.Response
+	}
+	for _, hook := range c.afterSend {
+		hook(ctx, response, err)
+	}
+	for _, hook := range c.afterSendResult {
+		hook(ctx, result, err)
+	}
+}
+
+// metadataKey is the context key under which send metadata is stored. It is
+// an unexported struct type, so no other package can collide with it.
+type metadataKey struct{}
+
+// ContextWithMetadata returns a copy of ctx carrying metadata for the sends
+// made with it. The metadata is never sent to the server; it is only there
+// for hooks, which read it back with MetadataFromContext. Metadata already
+// on ctx is kept, with md taking precedence for duplicate keys.
+func ContextWithMetadata(ctx context.Context, md map[string]string) context.Context {
+	merged := make(map[string]string, len(md))
+	for k, v := range MetadataFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range md {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+// MetadataFromContext returns the metadata attached with ContextWithMetadata,
+// or nil if there is none. The returned map must not be modified.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
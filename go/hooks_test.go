@@ -0,0 +1,77 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAfterSendResultTransport(t *testing.T) {
+	var methods []string
+	var transports []Transport
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		respondSuccess(w, r)
+	}, WithSendMode(SendModeAuto), WithAfterSendResult(func(ctx context.Context, result *SendResult, err error) {
+		if err != nil {
+			t.Errorf("send failed: %v", err)
+		}
+		transports = append(transports, result.Transport)
+	}))
+
+	for _, body := range []string{"one line", "two\nlines"} {
+		if _, err := client.SendWithResult(context.Background(), NotificationOptions{Body: body}); err != nil {
+			t.Fatalf("SendWithResult: %v", err)
+		}
+	}
+
+	want := []Transport{TransportGET, TransportPOST}
+	if len(transports) != len(want) || len(methods) != len(want) {
+		t.Fatalf("transports = %v, methods = %v, want %v", transports, methods, want)
+	}
+	for i := range want {
+		if transports[i] != want[i] || methods[i] != string(want[i]) {
+			t.Errorf("send %d: hook saw %s, request was %s, want %s", i, transports[i], methods[i], want[i])
+		}
+	}
+}
+
+func TestRequestModifierPingAndServerInfo(t *testing.T) {
+	var unmodified []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth") != "secret" {
+			unmodified = append(unmodified, r.URL.Path)
+		}
+		if r.URL.Path == "/info" {
+			_, _ = w.Write([]byte(`{"version":"v2.2.0"}`))
+			return
+		}
+		respondSuccess(w, r)
+	}, WithRequestModifier(func(req *http.Request) error {
+		req.Header.Set("X-Auth", "secret")
+		return nil
+	}))
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if _, err := client.ServerInfo(context.Background()); err != nil {
+		t.Fatalf("ServerInfo: %v", err)
+	}
+	if len(unmodified) != 0 {
+		t.Errorf("requests to %v sent without the modifier's header", unmodified)
+	}
+
+	failing := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request to %s sent despite the modifier failing", r.URL.Path)
+	}, WithRequestModifier(func(req *http.Request) error {
+		return errors.New("no token")
+	}))
+	if err := failing.Ping(context.Background()); err == nil {
+		t.Error("Ping succeeded, want the modifier's error")
+	}
+	if _, err := failing.ServerInfo(context.Background()); err == nil {
+		t.Error("ServerInfo succeeded, want the modifier's error")
+	}
+}
@@ -0,0 +1,23 @@
+//go:build go1.24
+
+package bark
+
+import "net/http"
+
+// configureHTTP2 sets the protocols of t for WithHTTP2
+func configureHTTP2(t *http.Transport, h2c bool) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP2(true)
+	if h2c {
+		protocols.SetUnencryptedHTTP2(true)
+	} else {
+		protocols.SetHTTP1(true)
+	}
+	t.Protocols = protocols
+}
+
+// validateHTTP2 accepts every WithHTTP2 setting, all are supported since
+// Go 1.24
+func (c *Client) validateHTTP2() error {
+	return nil
+}
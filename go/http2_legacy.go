@@ -0,0 +1,18 @@
+//go:build !go1.24
+
+package bark
+
+import "net/http"
+
+// configureHTTP2 sets the protocols of t for WithHTTP2. Before Go 1.24
+// net/http can't speak h2c, so only HTTP/2 over TLS is enabled, by
+// ForceAttemptHTTP2.
+func configureHTTP2(t *http.Transport, h2c bool) {}
+
+// validateHTTP2 rejects h2c, which net/http only supports from Go 1.24
+func (c *Client) validateHTTP2() error {
+	if c.h2c {
+		return ErrH2CUnsupported
+	}
+	return nil
+}
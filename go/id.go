@@ -0,0 +1,16 @@
+package bark
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newRandomID returns a random 128-bit identifier encoded as hex
+func newRandomID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic("bark: failed to generate random id: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
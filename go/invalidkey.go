@@ -0,0 +1,73 @@
+package bark
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrKeyDisabled is returned without contacting the server for sends with a
+// key the server rejected, see WithDisableOnInvalidKey. It matches
+// ErrInvalidKey via errors.Is.
+var ErrKeyDisabled = fmt.Errorf("sends disabled after the server rejected the key: %w", ErrInvalidKey)
+
+// disabledKeys holds the keys disabled by WithDisableOnInvalidKey
+type disabledKeys struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+// WithDisableOnInvalidKey stops sending with a key once the server reported
+// it as unknown, e.g. because the Bark app was reinstalled. The send that
+// hit the error returns it as usual, later sends with the key fail right
+// away with ErrKeyDisabled and a warning is logged. Sends with other keys,
+// such as a new one set with SetKey, are not affected. ResetDisabledKeys
+// enables the keys again.
+func WithDisableOnInvalidKey() Option {
+	return func(c *Client) {
+		c.disableOnInvalidKey = true
+	}
+}
+
+// KeyDisabled reports whether sends with the client's key are disabled by
+// WithDisableOnInvalidKey
+func (c *Client) KeyDisabled() bool {
+	return c.keyDisabled(c.currentKey())
+}
+
+// ResetDisabledKeys enables the keys disabled by WithDisableOnInvalidKey
+// again, for when they have been registered anew
+func (c *Client) ResetDisabledKeys() {
+	c.disabledKeys.mu.Lock()
+	defer c.disabledKeys.mu.Unlock()
+	c.disabledKeys.keys = nil
+}
+
+// keyDisabled reports whether sends with key are disabled
+func (c *Client) keyDisabled(key string) bool {
+	if !c.disableOnInvalidKey {
+		return false
+	}
+
+	c.disabledKeys.mu.Lock()
+	defer c.disabledKeys.mu.Unlock()
+	return c.disabledKeys.keys[key]
+}
+
+// recordKeyOutcome disables key when err shows the server doesn't know it
+func (c *Client) recordKeyOutcome(key string, err error) {
+	if !c.disableOnInvalidKey || !errors.Is(err, ErrInvalidKey) {
+		return
+	}
+
+	c.disabledKeys.mu.Lock()
+	defer c.disabledKeys.mu.Unlock()
+	if c.disabledKeys.keys[key] {
+		return
+	}
+	if c.disabledKeys.keys == nil {
+		c.disabledKeys.keys = make(map[string]bool)
+	}
+	c.disabledKeys.keys[key] = true
+	c.warnf(WarningKeyDisabled, "disabling sends with key %s, the server doesn't know it", c.redactedKey(key))
+}
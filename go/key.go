@@ -0,0 +1,42 @@
+package bark
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// ErrMalformedKey is returned when a Bark key contains characters that can't
+// be part of a valid key
+var ErrMalformedKey = errors.New("malformed bark key")
+
+// WithLaxKeyValidation relaxes key validation for custom servers whose keys
+// aren't plain alphanumerics. Only characters that would break the request
+// URL (whitespace, control characters, '/', '?', '#' and '%') are rejected.
+func WithLaxKeyValidation() Option {
+	return func(c *Client) {
+		c.laxKeyValidation = true
+	}
+}
+
+// validateKey checks that key only contains characters allowed in a Bark
+// key. Keys issued by the Bark app are alphanumeric, typically 22 characters.
+func (c *Client) validateKey(key string) error {
+	for _, r := range key {
+		if c.laxKeyValidation {
+			if unicode.IsSpace(r) || unicode.IsControl(r) || r == '/' || r == '?' || r == '#' || r == '%' {
+				return fmt.Errorf("%w: contains %q", ErrMalformedKey, r)
+			}
+			continue
+		}
+		if !isASCIIAlphanumeric(r) {
+			return fmt.Errorf("%w: contains %q, expected only letters and digits (use WithLaxKeyValidation for custom keys)", ErrMalformedKey, r)
+		}
+	}
+	return nil
+}
+
+// isASCIIAlphanumeric checks if r is an ASCII letter or digit
+func isASCIIAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,127 @@
+package bark
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyRouter is the state of latency-based server selection
+type latencyRouter struct {
+	interval time.Duration
+
+	// latencies holds the last ping latency per server URL, negative for
+	// servers that failed it
+	mu        sync.RWMutex
+	latencies map[string]time.Duration
+
+	// cancel stops the prober, done is closed once it has exited
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// WithLatencyRouting pings the primary and failover servers every interval
+// in the background and sends to the fastest one that answered, falling
+// over to the others in order of their latency. Servers whose last ping
+// failed are tried last. Until the first round of pings has finished,
+// servers are used in the order they were configured.
+//
+// It has no effect without WithFailoverServers. The prober runs from
+// NewClient until Shutdown or Close.
+func WithLatencyRouting(interval time.Duration) Option {
+	return func(c *Client) {
+		c.latency.interval = interval
+	}
+}
+
+// startLatencyRouting starts the background prober if one is configured
+func (c *Client) startLatencyRouting() {
+	if c.latency.interval <= 0 || len(c.failoverServers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.latency.cancel = cancel
+	c.latency.done = make(chan struct{})
+	go c.runLatencyRouting(ctx)
+}
+
+// runLatencyRouting measures the servers every interval until ctx is
+// cancelled. Each round of pings may take up to one interval.
+func (c *Client) runLatencyRouting(ctx context.Context) {
+	defer close(c.latency.done)
+
+	for {
+		servers := c.servers()
+		latencies := make([]time.Duration, len(servers))
+		pingCtx, cancel := context.WithTimeout(ctx, c.latency.interval)
+		fanOut(len(servers), len(servers), func(i int) {
+			start := c.clock.Now()
+			if err := c.ping(pingCtx, servers[i]); err != nil {
+				latencies[i] = -1
+				return
+			}
+			latencies[i] = c.clock.Now().Sub(start)
+		})
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		measured := make(map[string]time.Duration, len(servers))
+		for i, serverURL := range servers {
+			measured[serverURL] = latencies[i]
+		}
+		c.latency.mu.Lock()
+		c.latency.latencies = measured
+		c.latency.mu.Unlock()
+
+		if c.sleep(ctx, c.latency.interval) != nil {
+			return
+		}
+	}
+}
+
+// routedServers returns the servers to try for a send, fastest first when
+// latency routing is enabled. Servers without a measurement yet, such as one
+// set with SetServerURL since the last round, come after the ones that
+// answered, and servers that failed their ping come last.
+func (c *Client) routedServers() []string {
+	servers := c.servers()
+
+	c.latency.mu.RLock()
+	defer c.latency.mu.RUnlock()
+	if len(c.latency.latencies) == 0 {
+		return servers
+	}
+
+	rank := func(serverURL string) (int, time.Duration) {
+		latency, ok := c.latency.latencies[serverURL]
+		switch {
+		case !ok:
+			return 1, 0
+		case latency < 0:
+			return 2, 0
+		}
+		return 0, latency
+	}
+	sort.SliceStable(servers, func(i, j int) bool {
+		rankI, latencyI := rank(servers[i])
+		rankJ, latencyJ := rank(servers[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return latencyI < latencyJ
+	})
+	return servers
+}
+
+// stopLatencyRouting stops the background prober and waits for it to exit
+func (c *Client) stopLatencyRouting() {
+	if c.latency.cancel == nil {
+		return
+	}
+	c.latency.cancel()
+	<-c.latency.done
+}
@@ -0,0 +1,63 @@
+package bark
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLatencyRouting(t *testing.T) {
+	newServer := func(pingDelay time.Duration, pingStatus int, sends *atomic.Int32) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/ping" {
+				time.Sleep(pingDelay)
+				if pingStatus != http.StatusOK {
+					http.Error(w, "unavailable", pingStatus)
+					return
+				}
+				respondSuccess(w, r)
+				return
+			}
+			sends.Add(1)
+			respondSuccess(w, r)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	var slowSends, failingSends, fastSends atomic.Int32
+	slow := newServer(50*time.Millisecond, http.StatusOK, &slowSends)
+	failing := newServer(0, http.StatusServiceUnavailable, &failingSends)
+	fast := newServer(0, http.StatusOK, &fastSends)
+
+	client, err := NewClient("testkey", slow.URL, WithFailoverServers(failing.URL, fast.URL), WithLatencyRouting(time.Second))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	// Routing only starts once the first round of pings is recorded
+	waitFor(t, "the first round of pings", func() bool {
+		client.latency.mu.RLock()
+		defer client.latency.mu.RUnlock()
+		return client.latency.latencies != nil
+	})
+
+	want := []string{fast.URL, slow.URL, failing.URL}
+	got := client.routedServers()
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("routed servers = %v, want fast, slow, failing: %v", got, want)
+		}
+	}
+
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if fastSends.Load() != 1 || slowSends.Load() != 0 || failingSends.Load() != 0 {
+		t.Errorf("sends fast/slow/failing = %d/%d/%d, want only the fastest server used",
+			fastSends.Load(), slowSends.Load(), failingSends.Load())
+	}
+}
@@ -0,0 +1,43 @@
+package bark
+
+import "context"
+
+// WithMaxConcurrency caps the number of requests the client has in flight
+// at once, across all send paths, batches and the queue worker included.
+// A request that would exceed the cap waits for a free slot, or fails with
+// the context's error when its context is done first. By default the
+// number of requests is not limited.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+// acquireSlot waits for a free request slot or until ctx is done
+func (c *Client) acquireSlot(ctx context.Context) error {
+	if c.inFlight == nil {
+		return nil
+	}
+	select {
+	case c.inFlight <- struct{}{}:
+		return nil
+	default:
+	}
+
+	c.notifyWait(WaitConcurrency, 0)
+	select {
+	case c.inFlight <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseSlot frees a slot taken with acquireSlot
+func (c *Client) releaseSlot() {
+	if c.inFlight != nil {
+		<-c.inFlight
+	}
+}
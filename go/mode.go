@@ -0,0 +1,105 @@
+package bark
+
+import (
+	"context"
+	"strings"
+)
+
+// SendMode selects the HTTP method notifications are sent with
+type SendMode int
+
+const (
+	// SendModeGET sends the options in the URL path and query string
+	SendModeGET SendMode = iota
+
+	// SendModePOST sends the options as a JSON body
+	SendModePOST
+
+	// SendModeAuto uses POST for encrypted notifications, multi-line bodies
+	// and notifications whose GET URL would be longer than the auto
+	// threshold, and GET otherwise
+	SendModeAuto
+)
+
+// Transport is the HTTP method a notification was actually sent with
+type Transport string
+
+const (
+	// TransportGET means the options were sent in the URL
+	TransportGET Transport = "GET"
+
+	// TransportPOST means the options were sent as a JSON body
+	TransportPOST Transport = "POST"
+)
+
+// DefaultAutoThreshold is the GET URL length above which SendModeAuto
+// switches to POST, safely below the ~2000 characters many proxies accept
+const DefaultAutoThreshold = 2000
+
+// String returns the name of the send mode
+func (m SendMode) String() string {
+	switch m {
+	case SendModeGET:
+		return "GET"
+	case SendModePOST:
+		return "POST"
+	case SendModeAuto:
+		return "Auto"
+	default:
+		return "SendMode(unknown)"
+	}
+}
+
+// transport returns the Transport a resolved send mode uses
+func (m SendMode) transport() Transport {
+	if m == SendModePOST {
+		return TransportPOST
+	}
+	return TransportGET
+}
+
+// WithSendMode sets the send mode used by SendWithResult, GET by default.
+// Send and SendPost always use GET and POST respectively.
+func WithSendMode(mode SendMode) Option {
+	return func(c *Client) {
+		c.sendMode = mode
+	}
+}
+
+// WithAutoThreshold sets the GET URL length above which SendModeAuto
+// switches to POST, DefaultAutoThreshold by default
+func WithAutoThreshold(length int) Option {
+	return func(c *Client) {
+		if length > 0 {
+			c.autoThreshold = length
+		}
+	}
+}
+
+// SendWithMode is like SendWithResult with the send mode given per call,
+// overriding the client's default
+func (c *Client) SendWithMode(ctx context.Context, options NotificationOptions, mode SendMode) (*SendResult, error) {
+	return c.send(ctx, c.currentKey(), options, mode)
+}
+
+// resolveMode turns SendModeAuto into the concrete mode to use for options
+func (c *Client) resolveMode(mode SendMode, key string, options NotificationOptions) SendMode {
+	if mode != SendModeAuto {
+		return mode
+	}
+	// Some proxies in front of Bark servers decode or collapse line breaks in
+	// the path, while a JSON body always keeps them intact
+	if options.Ciphertext != "" || strings.ContainsAny(options.Body, "\r\n") {
+		return SendModePOST
+	}
+	// Actions can't be carried in a GET URL
+	if len(options.Actions) > 0 {
+		return SendModePOST
+	}
+
+	req, err := c.newGetRequest(context.Background(), c.currentServerURL(), key, options)
+	if err != nil || len(req.URL.String()) > c.autoThreshold {
+		return SendModePOST
+	}
+	return SendModeGET
+}
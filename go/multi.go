@@ -0,0 +1,155 @@
+package bark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// minMultiPushVersion is the first server version accepting "device_keys"
+const minMultiPushVersion = "v2.2.0"
+
+// multiPushRequest is the POST body of a push to several devices at once
+type multiPushRequest struct {
+	NotificationOptions
+	DeviceKeys []string `json:"device_keys"`
+}
+
+// deviceResult is the per-device outcome of a multi-device push
+type deviceResult struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	DeviceKey string `json:"device_key"`
+}
+
+// SendMulti sends the same notification to several keys in a single POST to
+// the server's /push endpoint using its "device_keys" parameter. The push
+// goes through the same hooks, validation, quiet hours and other processing
+// as a single send; options that fail validation or a before hook are
+// rejected with the returned error.
+//
+// Servers older than v2.2.0, or whose version can't be determined, don't
+// accept device_keys; for those SendMulti falls back to SendBatch and sends
+// one request per key. Results are returned in the order of keys.
+func (c *Client) SendMulti(ctx context.Context, keys []string, options NotificationOptions) (BatchResults, error) {
+	if len(keys) == 0 {
+		return BatchResults{}, nil
+	}
+
+	info, err := c.cachedServerInfo(ctx)
+	if err != nil || !info.AtLeast(minMultiPushVersion) {
+		return c.SendBatch(ctx, keys, options)
+	}
+
+	result, err := c.runSend(ctx, options, func(ctx context.Context, options NotificationOptions) (*SendResult, error) {
+		return c.sendMultiValidated(ctx, keys, options)
+	})
+	if err != nil && result == nil {
+		return nil, err
+	}
+
+	results := make(BatchResults, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+		if err != nil {
+			results[i].Err = err
+		} else {
+			results[i].Response = result.Response
+		}
+	}
+	if err != nil || result.Suppressed {
+		return results, nil
+	}
+
+	// Servers report per-device outcomes in the response data; apply them
+	// where present and keep the overall outcome otherwise
+	applyDeviceResults(results, result.Response)
+	return results, nil
+}
+
+// sendMultiValidated prepares and validates the options like sendValidated
+// and pushes them to all keys at once
+func (c *Client) sendMultiValidated(ctx context.Context, keys []string, options NotificationOptions) (*SendResult, error) {
+	options, _, suppress, err := c.prepareSend(ctx, "", options, SendModePOST)
+	if err != nil {
+		return nil, err
+	}
+	if suppress {
+		return newSuppressedResult(), nil
+	}
+
+	result, err := c.execute(ctx, "", func(serverURL string) (*http.Request, error) {
+		return c.newMultiPushRequest(ctx, serverURL, keys, options)
+	})
+	if result != nil {
+		result.Transport = TransportPOST
+	}
+	return result, err
+}
+
+// newMultiPushRequest creates a POST request to /push for several device keys
+func (c *Client) newMultiPushRequest(ctx context.Context, serverURL string, keys []string, options NotificationOptions) (*http.Request, error) {
+	data, err := c.marshal(multiPushRequest{
+		NotificationOptions: options,
+		DeviceKeys:          keys,
+	})
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to marshal request body: %v", err),
+			Err:     err,
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverURL+"/push", bytes.NewReader(data))
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
+		}
+	}
+	req.Header.Set("Content-Type", c.postContentType())
+
+	return req, nil
+}
+
+// applyDeviceResults updates results with the per-device outcomes found in
+// the response data, if any
+func applyDeviceResults(results []BatchResult, response *Response) {
+	if response == nil || response.Data == nil {
+		return
+	}
+
+	// Data was decoded generically, so round-trip it into the typed form
+	raw, err := json.Marshal(response.Data)
+	if err != nil {
+		return
+	}
+	var devices []deviceResult
+	if err := json.Unmarshal(raw, &devices); err != nil {
+		return
+	}
+
+	byKey := make(map[string]deviceResult, len(devices))
+	for _, device := range devices {
+		byKey[device.DeviceKey] = device
+	}
+
+	for i := range results {
+		device, ok := byKey[results[i].Key]
+		if !ok {
+			continue
+		}
+		deviceResponse := &Response{Code: device.Code, Message: device.Message}
+		if device.Code != http.StatusOK {
+			results[i].Response = nil
+			results[i].Err = &BarkError{
+				Message:  fmt.Sprintf("API error: %s", device.Message),
+				Response: deviceResponse,
+			}
+			continue
+		}
+		results[i].Response = deviceResponse
+	}
+}
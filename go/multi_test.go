@@ -0,0 +1,85 @@
+package bark
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// multiPushServer answers /info with a version supporting multi-device
+// pushes and records the pushes it receives
+func multiPushServer(t *testing.T, pushes *[]map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/info":
+			_, _ = w.Write([]byte(`{"version":"v2.2.0"}`))
+		case "/push":
+			*pushes = append(*pushes, receivedParams(t, r))
+			respondSuccess(w, r)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			respondSuccess(w, r)
+		}
+	}
+}
+
+func TestSendMultiQuietHours(t *testing.T) {
+	var pushes []map[string]string
+	var afterSend int
+	client := newTestClient(t, multiPushServer(t, &pushes),
+		WithQuietHours(0, 24*time.Hour-time.Second, time.UTC, QuietHoursSuppress),
+		WithClock(fixedClock{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}),
+		WithAfterSendResult(func(ctx context.Context, result *SendResult, err error) {
+			afterSend++
+			if err != nil || !result.Suppressed {
+				t.Errorf("after hook saw %+v, %v, want a suppressed result", result, err)
+			}
+		}))
+
+	results, err := client.SendMulti(context.Background(), []string{"key1", "key2"}, NotificationOptions{Body: "hello"})
+	if err != nil {
+		t.Fatalf("SendMulti: %v", err)
+	}
+	if len(pushes) != 0 {
+		t.Errorf("%d pushes sent during quiet hours, want none", len(pushes))
+	}
+	if afterSend != 1 {
+		t.Errorf("after hook ran %d times, want once", afterSend)
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Response == nil {
+			t.Errorf("result %+v, want the suppressed response", result)
+		}
+	}
+}
+
+func TestSendMultiHooks(t *testing.T) {
+	var pushes []map[string]string
+	client := newTestClient(t, multiPushServer(t, &pushes),
+		WithBeforeSend(func(ctx context.Context, options *NotificationOptions) error {
+			options.Title = "from hook"
+			return nil
+		}))
+
+	results, err := client.SendMulti(context.Background(), []string{"key1", "key2"}, NotificationOptions{Body: "hello"})
+	if err != nil {
+		t.Fatalf("SendMulti: %v", err)
+	}
+	if len(pushes) != 1 {
+		t.Fatalf("%d pushes, want 1", len(pushes))
+	}
+	if pushes[0]["title"] != "from hook" || pushes[0]["device_keys"] != `["key1","key2"]` {
+		t.Errorf("push = %v, want the hook's title for both keys", pushes[0])
+	}
+	if summary := results.Summary(); summary.Succeeded != 2 {
+		t.Errorf("summary = %+v, want 2 successes", summary)
+	}
+
+	if _, err := client.SendMulti(context.Background(), []string{"key1"}, NotificationOptions{Body: "hello", Level: "loud"}); err == nil {
+		t.Error("SendMulti with an invalid level succeeded, want a validation error")
+	}
+	if len(pushes) != 1 {
+		t.Errorf("%d pushes, want the invalid notification not sent", len(pushes))
+	}
+}
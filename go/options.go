@@ -0,0 +1,233 @@
+package bark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Option configures optional behavior of a Client
+type Option func(*Client)
+
+// WithServerURL sets the Bark server URL, overriding the one passed to NewClient
+func WithServerURL(serverURL string) Option {
+	return func(c *Client) {
+		if serverURL != "" {
+			c.ServerURL = serverURL
+		}
+	}
+}
+
+// WithSuccessStatusCodes sets the HTTP status codes that are treated as an
+// accepted push. Defaults to 200, 202 and 204 rather than 200 alone, so that
+// proxies and CDNs acknowledging pushes with an empty 202 or 204 work out of
+// the box; pass []int{200} to accept nothing but 200.
+//
+// This only changes which HTTP statuses are accepted: when the response has
+// a JSON body, its "code" field must still be 200.
+func WithSuccessStatusCodes(codes []int) Option {
+	return func(c *Client) {
+		c.successStatusCodes = append([]int(nil), codes...)
+	}
+}
+
+// WithoutBodyCodeCheck skips validation of the "code" field in the JSON
+// response body, so success is decided by the HTTP status code alone.
+//
+// This is meant for minimal self-hosted servers that answer 200 without a
+// Bark-style JSON body. The response body is still parsed when possible.
+func WithoutBodyCodeCheck() Option {
+	return func(c *Client) {
+		c.skipBodyCodeCheck = true
+	}
+}
+
+// WithoutResponseParsing skips decoding response bodies, for high-volume
+// fire-and-forget sends. Bodies are discarded and sends return an empty
+// Response with only StatusCode and Latency set. Error statuses still fail
+// the send, classified by their status code alone. Implies
+// WithoutBodyCodeCheck.
+func WithoutResponseParsing() Option {
+	return func(c *Client) {
+		c.skipResponseParsing = true
+	}
+}
+
+// WithIdempotency sets an Idempotency-Key header on every request. The key is
+// generated once per logical send and reused for all of its retries, so a
+// server that supports it can drop duplicate deliveries. Servers without
+// support simply ignore the header.
+func WithIdempotency() Option {
+	return func(c *Client) {
+		c.idempotency = true
+	}
+}
+
+// isReplayed reports whether the server answered with the stored outcome of
+// an earlier request with the same Idempotency-Key, which it marks with an
+// "Idempotent-Replayed: true" header
+func isReplayed(header http.Header) bool {
+	return strings.EqualFold(header.Get("Idempotent-Replayed"), "true")
+}
+
+// MarshalFunc encodes a request body to JSON
+type MarshalFunc func(v any) ([]byte, error)
+
+// WithMarshaler replaces encoding/json for encoding POST request bodies, for
+// example with a faster JSON library or one with custom field ordering.
+// marshal must produce JSON compatible with encoding/json for the same value.
+func WithMarshaler(marshal MarshalFunc) Option {
+	return func(c *Client) {
+		if marshal != nil {
+			c.marshaler = marshal
+		}
+	}
+}
+
+// marshal encodes v with the configured marshaler, encoding/json by default
+func (c *Client) marshal(v any) ([]byte, error) {
+	if c.marshaler != nil {
+		return c.marshaler(v)
+	}
+	return json.Marshal(v)
+}
+
+// WithParamOrder sets the order of the query parameters of GET requests, for
+// servers that expect a specific order, e.g. because they sign the query.
+// Parameters are emitted in the order of names, followed by any others in
+// alphabetical order. By default all parameters are sorted alphabetically.
+func WithParamOrder(names ...string) Option {
+	return func(c *Client) {
+		c.paramOrder = append([]string(nil), names...)
+	}
+}
+
+// WithQueryStyleGET sends the title, subtitle and body of GET requests as
+// query parameters instead of path segments, e.g. /KEY?body=...&title=...,
+// for compatible servers that prefer it. It sidesteps the escaping problems
+// of slashes and hashes in path segments. Combined with WithPathTemplate,
+// the segments of the template holding these fields are dropped as they are
+// for POST. The default is the path style of the Bark server.
+func WithQueryStyleGET() Option {
+	return func(c *Client) {
+		c.queryStyleGET = true
+	}
+}
+
+// encodeQuery encodes params like url.Values.Encode, in the order set with
+// WithParamOrder
+func (c *Client) encodeQuery(params url.Values) string {
+	if c.paramOrder == nil {
+		return params.Encode()
+	}
+
+	var b strings.Builder
+	write := func(name string) {
+		for _, value := range params[name] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(name))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+
+	seen := make(map[string]bool, len(c.paramOrder))
+	for _, name := range c.paramOrder {
+		if !seen[name] {
+			seen[name] = true
+			write(name)
+		}
+	}
+
+	rest := make([]string, 0, len(params))
+	for name := range params {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		write(name)
+	}
+	return b.String()
+}
+
+// WithContextTimeout bounds every send with a deadline of d from its start,
+// covering all of its attempts, retry waits and failover, and applied on top
+// of the context passed in. HTTPClient.Timeout, by contrast, limits each
+// single request. This gives Send and SendPost, which take no context, an
+// end-to-end deadline.
+func WithContextTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.sendTimeout = d
+	}
+}
+
+// withSendTimeout applies the timeout set by WithContextTimeout to ctx
+func (c *Client) withSendTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.sendTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.sendTimeout)
+}
+
+// DefaultContentType is the Content-Type of POST requests
+const DefaultContentType = "application/json"
+
+// ErrInvalidContentType is returned by NewClient when the content type set
+// with WithContentType is not a valid media type
+var ErrInvalidContentType = errors.New("invalid content type")
+
+// WithContentType sets the Content-Type header of POST requests, e.g.
+// "application/json; charset=utf-8" for proxies that insist on it. The body
+// is JSON whatever the header says. Defaults to DefaultContentType.
+func WithContentType(contentType string) Option {
+	return func(c *Client) {
+		c.contentType = contentType
+	}
+}
+
+// postContentType returns the Content-Type of POST requests
+func (c *Client) postContentType() string {
+	if c.contentType == "" {
+		return DefaultContentType
+	}
+	return c.contentType
+}
+
+// validateContentType checks the content type set with WithContentType
+func (c *Client) validateContentType() error {
+	if c.contentType == "" {
+		return nil
+	}
+	if _, _, err := mime.ParseMediaType(c.contentType); err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidContentType, c.contentType, err)
+	}
+	return nil
+}
+
+// WithAcceptLanguage sets the Accept-Language header of every request, e.g.
+// "en" to get a self-hosted server's error messages in BarkError in English
+// whatever its locale. A header set by a RequestModifier wins. By default no
+// Accept-Language header is sent.
+func WithAcceptLanguage(language string) Option {
+	return func(c *Client) {
+		c.acceptLanguage = language
+	}
+}
+
+// setAcceptLanguage sets the Accept-Language header of req, unless it has one
+func (c *Client) setAcceptLanguage(req *http.Request) {
+	if c.acceptLanguage != "" && req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
+}
@@ -0,0 +1,96 @@
+package bark
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParamOrder(t *testing.T) {
+	options := NotificationOptions{
+		Body:  "hello",
+		Sound: "bell",
+		Group: "ops",
+		Level: LevelActive,
+		Badge: Int(2),
+		URL:   "https://example.com/?a=b",
+	}
+
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		query string
+	}{
+		{
+			name:  "sorted by default",
+			query: "badge=2&group=ops&level=active&sound=bell&url=https%3A%2F%2Fexample.com%2F%3Fa%3Db",
+		},
+		{
+			name:  "given order first, rest sorted",
+			opts:  []Option{WithParamOrder("url", "sound", "missing", "group")},
+			query: "url=https%3A%2F%2Fexample.com%2F%3Fa%3Db&sound=bell&group=ops&badge=2&level=active",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				respondSuccess(w, r)
+			}, tt.opts...)
+
+			if _, err := client.Send(options); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if query != tt.query {
+				t.Errorf("query = %q, want %q", query, tt.query)
+			}
+		})
+	}
+}
+
+func TestQueryStyleGET(t *testing.T) {
+	options := NotificationOptions{Title: "Build #42", Subtitle: "main/dev", Body: "50% done\nnext", Group: "ci"}
+
+	for _, tt := range []struct {
+		name        string
+		opts        []Option
+		escapedPath string
+		params      map[string]string
+	}{
+		{
+			name:        "path style",
+			escapedPath: "/testkey/Build%20%2342/main%2Fdev/50%25%20done%0Anext",
+			params:      map[string]string{"group": "ci"},
+		},
+		{
+			name:        "query style",
+			opts:        []Option{WithQueryStyleGET()},
+			escapedPath: "/testkey",
+			params:      map[string]string{"title": "Build #42", "subtitle": "main/dev", "body": "50% done\nnext", "group": "ci"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var escapedPath string
+			var params map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				escapedPath = r.URL.EscapedPath()
+				params = receivedParams(t, r)
+				respondSuccess(w, r)
+			}, tt.opts...)
+
+			if _, err := client.Send(options); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if escapedPath != tt.escapedPath {
+				t.Errorf("path = %q, want %q", escapedPath, tt.escapedPath)
+			}
+			if len(params) != len(tt.params) {
+				t.Errorf("params = %v, want %v", params, tt.params)
+			}
+			for name, want := range tt.params {
+				if params[name] != want {
+					t.Errorf("%s = %q, want %q", name, params[name], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package bark
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseOptions builds notification options from Bark parameters, such as a
+// template stored as a query string. The parameter names are the ones the
+// Bark server understands ("body", "title", "group", "isArchive", ...);
+// unknown names are ignored. Levels must be one of the Level constants and
+// "call" and "isArchive" must be booleans ("1", "true", "0", "false", ...)
+// and "badge" and "volume" integers.
+//
+// When a parameter is given more than once, the first value is used.
+func ParseOptions(values url.Values) (NotificationOptions, error) {
+	var options NotificationOptions
+
+	stringFields := map[string]*string{
+		"body":          &options.Body,
+		"title":         &options.Title,
+		"subtitle":      &options.Subtitle,
+		paramURL:        &options.URL,
+		paramGroup:      &options.Group,
+		paramID:         &options.ID,
+		paramIcon:       &options.Icon,
+		paramImage:      &options.Image,
+		paramSound:      &options.Sound,
+		paramLevel:      &options.Level,
+		paramCopy:       &options.Copy,
+		paramCiphertext: &options.Ciphertext,
+		paramIV:         &options.IV,
+	}
+	for name, field := range stringFields {
+		if _, ok := values[name]; ok {
+			*field = values.Get(name)
+		}
+	}
+
+	for _, name := range []string{paramCall, paramIsArchive} {
+		if _, ok := values[name]; !ok {
+			continue
+		}
+		v, err := strconv.ParseBool(values.Get(name))
+		if err != nil {
+			return options, fmt.Errorf("invalid %s %q: must be a boolean", name, values.Get(name))
+		}
+		if name == paramCall {
+			options.Call = v
+		} else {
+			options.IsArchive = &v
+		}
+	}
+
+	for _, name := range []string{paramBadge, paramVolume} {
+		if _, ok := values[name]; !ok {
+			continue
+		}
+		v, err := strconv.Atoi(values.Get(name))
+		if err != nil {
+			return options, fmt.Errorf("invalid %s %q: must be an integer", name, values.Get(name))
+		}
+		if name == paramBadge {
+			options.Badge = &v
+		} else {
+			options.Volume = &v
+		}
+	}
+
+	if options.Level != "" && !isValidLevel(options.Level) {
+		return options, fmt.Errorf("%w: %q", ErrInvalidLevel, options.Level)
+	}
+	return options, nil
+}
+
+// ParseOptionsString is like ParseOptions for a URL-encoded query string,
+// with or without a leading "?"
+func ParseOptionsString(query string) (NotificationOptions, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return NotificationOptions{}, fmt.Errorf("invalid query string: %w", err)
+	}
+	return ParseOptions(values)
+}
@@ -0,0 +1,102 @@
+package bark
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidPathTemplate is returned by NewClient when the path template
+// set with WithPathTemplate doesn't contain {key}
+var ErrInvalidPathTemplate = errors.New("invalid path template. must contain {key}")
+
+// Path template tokens substituted by WithPathTemplate
+const (
+	tokenKey      = "{key}"
+	tokenTitle    = "{title}"
+	tokenSubtitle = "{subtitle}"
+	tokenBody     = "{body}"
+)
+
+// WithPathTemplate sets the request path for Bark-compatible servers with
+// different routing, e.g. "/send/{key}" or "/send/{key}/{title}/{body}".
+// The template is appended to the server URL and must contain {key}.
+//
+// GET requests substitute {title}, {subtitle} and {body}, dropping path
+// segments that end up empty; those of the three the template doesn't place
+// are sent as query parameters instead. POST requests, which carry them in
+// the JSON body, drop the segments holding these tokens. Multi-device pushes
+// keep using /push.
+//
+// Without a template the standard Bark routes are used.
+func WithPathTemplate(tmpl string) Option {
+	return func(c *Client) {
+		if tmpl != "" && !strings.HasPrefix(tmpl, "/") {
+			tmpl = "/" + tmpl
+		}
+		c.pathTemplate = tmpl
+	}
+}
+
+// validatePathTemplate checks the template set with WithPathTemplate
+func (c *Client) validatePathTemplate() error {
+	if c.pathTemplate != "" && !strings.Contains(c.pathTemplate, tokenKey) {
+		return ErrInvalidPathTemplate
+	}
+	return nil
+}
+
+// expandPath fills in the path template for key and, for GET requests, the
+// text fields of options. It returns the path and, for GET, the text fields
+// the template doesn't place, to be sent in the query.
+func (c *Client) expandPath(key string, options NotificationOptions, get bool) (string, url.Values) {
+	fields := []struct {
+		token, name, value string
+	}{
+		{tokenTitle, "title", options.Title},
+		{tokenSubtitle, "subtitle", options.Subtitle},
+		{tokenBody, "body", options.Body},
+	}
+
+	segments := strings.Split(strings.TrimPrefix(c.pathTemplate, "/"), "/")
+	var b strings.Builder
+	for _, segment := range segments {
+		hasField := false
+		for _, f := range fields {
+			if strings.Contains(segment, f.token) {
+				hasField = true
+				segment = strings.ReplaceAll(segment, f.token, escapePathSegment(f.value))
+			}
+		}
+		if hasField && !get {
+			continue
+		}
+		segment = strings.ReplaceAll(segment, tokenKey, key)
+		if segment == "" {
+			continue
+		}
+		b.WriteString("/")
+		b.WriteString(segment)
+	}
+
+	if !get {
+		return b.String(), nil
+	}
+	rest := url.Values{}
+	for _, f := range fields {
+		if f.value != "" && !strings.Contains(c.pathTemplate, f.token) {
+			rest.Set(f.name, f.value)
+		}
+	}
+	return b.String(), rest
+}
+
+// keyPath returns the path of requests carrying everything but the key in
+// their body
+func (c *Client) keyPath(key string) string {
+	if c.pathTemplate == "" {
+		return "/" + key
+	}
+	path, _ := c.expandPath(key, NotificationOptions{}, false)
+	return path
+}
@@ -0,0 +1,72 @@
+package bark
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPathTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		post        bool
+		options     NotificationOptions
+		escapedPath string
+		params      map[string]string
+	}{
+		{
+			name:        "get",
+			options:     NotificationOptions{Title: "Build", Subtitle: "main", Body: "hello world", Group: "ci"},
+			escapedPath: "/send/testkey/Build/hello%20world",
+			params:      map[string]string{"subtitle": "main", "group": "ci"},
+		},
+		{
+			name:        "get without title",
+			options:     NotificationOptions{Body: "a/b"},
+			escapedPath: "/send/testkey/a%2Fb",
+			params:      map[string]string{},
+		},
+		{
+			name:        "post",
+			post:        true,
+			options:     NotificationOptions{Title: "Build", Body: "hello world"},
+			escapedPath: "/send/testkey",
+			params:      map[string]string{"title": "Build", "body": "hello world"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var escapedPath string
+			var params map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				escapedPath = r.URL.EscapedPath()
+				params = receivedParams(t, r)
+				respondSuccess(w, r)
+			}, WithPathTemplate("send/{key}/{title}/{body}"))
+
+			send := client.Send
+			if tt.post {
+				send = client.SendPost
+			}
+			if _, err := send(tt.options); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			if escapedPath != tt.escapedPath {
+				t.Errorf("path = %q, want %q", escapedPath, tt.escapedPath)
+			}
+			for name, want := range tt.params {
+				if params[name] != want {
+					t.Errorf("%s = %q, want %q", name, params[name], want)
+				}
+			}
+			if !tt.post && len(params) != len(tt.params) {
+				t.Errorf("params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestPathTemplateWithoutKey(t *testing.T) {
+	if _, err := NewClient("testkey", "https://bark.test", WithPathTemplate("/send/{body}")); !errors.Is(err, ErrInvalidPathTemplate) {
+		t.Errorf("NewClient err = %v, want ErrInvalidPathTemplate", err)
+	}
+}
@@ -0,0 +1,35 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPercent is returned by SendProgress for a percentage outside 0-100
+var ErrInvalidPercent = errors.New("invalid percent. must be between 0 and 100")
+
+// SendProgress sends a progress notification such as "Backup — 42%". Every
+// update for the same id replaces the previous one on the device, so a
+// running task shows a single notification. The level is LevelPassive, so
+// updates don't alert the user, unless options sets another level.
+//
+// The body of options is replaced; the other fields are sent as given.
+func (c *Client) SendProgress(ctx context.Context, id string, percent int, label string, options NotificationOptions) (*SendResult, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+	if percent < 0 || percent > 100 {
+		return nil, ErrInvalidPercent
+	}
+
+	options.ID = id
+	options.Body = fmt.Sprintf("%d%%", percent)
+	if label != "" {
+		options.Body = fmt.Sprintf("%s — %d%%", label, percent)
+	}
+	if options.Level == "" {
+		options.Level = LevelPassive
+	}
+	return c.send(ctx, c.currentKey(), options, c.sendMode)
+}
@@ -0,0 +1,290 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueNotConfigured is returned by Enqueue when the client was created without WithQueue
+	ErrQueueNotConfigured = errors.New("send queue is not configured")
+
+	// ErrQueueFull is returned by Enqueue when the send queue has no free slot
+	ErrQueueFull = errors.New("send queue is full")
+)
+
+// DeadLetterFunc receives a notification that could not be delivered
+// together with the error of its last attempt
+type DeadLetterFunc func(options NotificationOptions, lastErr error)
+
+// WithQueue enables an in-memory send queue holding up to capacity
+// notifications. Notifications are added with Enqueue and delivered by a
+// background worker controlled with Start and Stop.
+func WithQueue(capacity int) Option {
+	return func(c *Client) {
+		if capacity > 0 {
+			c.queue = newSendQueue(capacity)
+		}
+	}
+}
+
+// WithDeadLetter sets the function called with notifications that could not
+// be delivered: queued notifications that failed all of their attempts, and
+// sends that failed after retrying with the policy set by WithRetry.
+//
+// fn is called exactly once per failed notification, on its own goroutine,
+// so a slow fn never holds up the queue worker or the caller of Send.
+func WithDeadLetter(fn DeadLetterFunc) Option {
+	return func(c *Client) {
+		c.deadLetter = fn
+	}
+}
+
+// WithQueueBackoff sets the delays between the queue's own attempts at
+// delivering a notification, DefaultBackoff unless set. It only applies when
+// the client has no retry policy, see WithQueue.
+func WithQueueBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.queueBackoff = &backoff
+	}
+}
+
+// sendQueue is a bounded FIFO ring buffer of pending notifications
+type sendQueue struct {
+	mu    sync.Mutex
+	items []NotificationOptions
+	head  int
+	size  int
+
+	// wake is signalled when an item is added
+	wake chan struct{}
+
+	// cancel stops the running worker, done is closed once it has exited
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// draining makes the worker exit once the queue is empty
+	draining bool
+}
+
+func newSendQueue(capacity int) *sendQueue {
+	return &sendQueue{
+		items: make([]NotificationOptions, capacity),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// push adds options at the back of the queue
+func (q *sendQueue) push(options NotificationOptions) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size == len(q.items) {
+		return ErrQueueFull
+	}
+	q.items[(q.head+q.size)%len(q.items)] = options
+	q.size++
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// pushFront puts options back at the front of the queue. The item is
+// dropped if the queue filled up in the meantime.
+func (q *sendQueue) pushFront(options NotificationOptions) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size == len(q.items) {
+		return false
+	}
+	q.head = (q.head - 1 + len(q.items)) % len(q.items)
+	q.items[q.head] = options
+	q.size++
+	return true
+}
+
+// drain returns and removes all items left in the queue
+func (q *sendQueue) drain() []NotificationOptions {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]NotificationOptions, 0, q.size)
+	for q.size > 0 {
+		items = append(items, q.items[q.head])
+		q.items[q.head] = NotificationOptions{}
+		q.head = (q.head + 1) % len(q.items)
+		q.size--
+	}
+	return items
+}
+
+// isDraining reports whether the worker should exit once the queue is empty
+func (q *sendQueue) isDraining() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.draining
+}
+
+// pop removes and returns the item at the front of the queue
+func (q *sendQueue) pop() (NotificationOptions, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size == 0 {
+		return NotificationOptions{}, false
+	}
+	options := q.items[q.head]
+	q.items[q.head] = NotificationOptions{}
+	q.head = (q.head + 1) % len(q.items)
+	q.size--
+	return options, true
+}
+
+// Enqueue validates the options and adds them to the send queue. The
+// notification is delivered by the queue worker once it is started.
+func (c *Client) Enqueue(options NotificationOptions) error {
+	if c.queue == nil {
+		return ErrQueueNotConfigured
+	}
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+	if _, err := c.prepareOptions(options); err != nil {
+		return err
+	}
+	return c.queue.push(options.Clone())
+}
+
+// Start starts the background worker that delivers queued notifications.
+// It does nothing if the queue is not configured, the worker is running or
+// the client has been shut down.
+func (c *Client) Start() {
+	q := c.queue
+	if q == nil || c.closed.Load() {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.done = make(chan struct{})
+	go c.runQueue(ctx, q.done)
+}
+
+// Stop stops the queue worker and waits for it to exit. A notification
+// interrupted by Stop is put back in the queue; pending notifications stay
+// queued until the worker is started again.
+func (c *Client) Stop() {
+	q := c.queue
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	cancel, done := q.cancel, q.done
+	q.cancel, q.done = nil, nil
+	q.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// runQueue delivers queued notifications until ctx is cancelled
+func (c *Client) runQueue(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		options, ok := c.queue.pop()
+		if !ok {
+			if c.queue.isDraining() {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.queue.wake:
+				continue
+			}
+		}
+
+		err := c.deliverQueued(ctx, options)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			// Interrupted by Stop, keep the notification for the next run
+			if !c.queue.pushFront(options) {
+				c.reportDeadLetter(options, err)
+			}
+			return
+		}
+		c.reportDeadLetter(options, err)
+	}
+}
+
+// deliverQueued sends a queued notification. When the client itself has no
+// retry policy, the queue retries with DefaultRetryPolicy, using the backoff
+// set by WithQueueBackoff if any, so short outages don't lose notifications.
+func (c *Client) deliverQueued(ctx context.Context, options NotificationOptions) error {
+	policy := RetryPolicy{MaxAttempts: 1}
+	if c.retryPolicy.MaxAttempts < 2 {
+		policy = DefaultRetryPolicy()
+		if c.queueBackoff != nil {
+			policy.Backoff = *c.queueBackoff
+		}
+	}
+
+	ctx = context.WithValue(ctx, queuedSendKey{}, true)
+
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		_, err := c.sendPost(ctx, c.currentKey(), options)
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retries(err) {
+			return err
+		}
+
+		delay = policy.next(attempt, delay)
+		if sleepErr := c.wait(ctx, WaitQueueRetry, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// reportDeadLetter hands an undeliverable notification to the dead-letter
+// function without waiting for it to return
+func (c *Client) reportDeadLetter(options NotificationOptions, err error) {
+	if c.deadLetter == nil {
+		return
+	}
+	c.deadLetters.Add(1)
+	c.deadLettersRunning.Add(1)
+	go func() {
+		defer c.deadLetters.Done()
+		defer c.deadLettersRunning.Add(-1)
+		c.deadLetter(options, err)
+	}()
+}
+
+// queuedSendKey marks the context of sends made by the queue worker, which
+// reports dead letters itself once its own attempts are exhausted
+type queuedSendKey struct{}
+
+// isQueuedSend reports whether ctx belongs to a send made by the queue worker
+func isQueuedSend(ctx context.Context) bool {
+	queued, _ := ctx.Value(queuedSendKey{}).(bool)
+	return queued
+}
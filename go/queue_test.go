@@ -0,0 +1,117 @@
+package bark
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueDelivers(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	delivered := make(chan struct{}, 3)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		bodies = append(bodies, receivedParams(t, r)["body"])
+		mu.Unlock()
+		respondSuccess(w, r)
+		delivered <- struct{}{}
+	}, WithQueue(2))
+
+	if err := client.Enqueue(NotificationOptions{Body: "first"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := client.Enqueue(NotificationOptions{Body: "second"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := client.Enqueue(NotificationOptions{Body: "third"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Enqueue on a full queue: err = %v, want ErrQueueFull", err)
+	}
+	if err := client.Enqueue(NotificationOptions{}); !errors.Is(err, ErrEmptyBody) {
+		t.Errorf("Enqueue without body: err = %v, want ErrEmptyBody", err)
+	}
+
+	client.Start()
+	defer client.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("queued notifications not delivered")
+		}
+	}
+
+	// Sends are accepted again once the worker drained the queue
+	if err := client.Enqueue(NotificationOptions{Body: "third"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("notification enqueued while running not delivered")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(bodies, ","); got != "first,second,third" {
+		t.Errorf("delivered %s, want first,second,third in order", got)
+	}
+}
+
+func TestQueueNotConfigured(t *testing.T) {
+	client := newTestClient(t, respondSuccess)
+	if err := client.Enqueue(NotificationOptions{Body: "hello"}); !errors.Is(err, ErrQueueNotConfigured) {
+		t.Errorf("err = %v, want ErrQueueNotConfigured", err)
+	}
+}
+
+func TestQueueDeadLetter(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	type deadLetter struct {
+		options NotificationOptions
+		err     error
+	}
+	deadLetters := make(chan deadLetter, 2)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}, WithQueue(1), WithClock(fixedClock{time.Now()}), WithDeadLetter(func(options NotificationOptions, lastErr error) {
+		deadLetters <- deadLetter{options, lastErr}
+	}))
+
+	if err := client.Enqueue(NotificationOptions{Body: "lost"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	client.Start()
+	defer client.Stop()
+
+	select {
+	case got := <-deadLetters:
+		if got.options.Body != "lost" {
+			t.Errorf("dead letter body = %q, want %q", got.options.Body, "lost")
+		}
+		if !errors.Is(got.err, ErrServerBusy) {
+			t.Errorf("dead letter err = %v, want the last attempt's ErrServerBusy", got.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no dead letter after the retries were exhausted")
+	}
+
+	client.Stop()
+	select {
+	case got := <-deadLetters:
+		t.Errorf("second dead letter %+v, want exactly one", got)
+	default:
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := DefaultRetryPolicy().MaxAttempts; requests != want {
+		t.Errorf("requests = %d, want the queue's %d attempts", requests, want)
+	}
+}
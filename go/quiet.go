@@ -0,0 +1,94 @@
+package bark
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSuppressed is returned by EncodeGET and EncodePOST for a notification
+// that WithQuietHours would not send. Sends succeed with
+// SendResult.Suppressed set instead.
+var ErrSuppressed = errors.New("notification suppressed during quiet hours")
+
+// QuietHoursMode selects what happens to non-critical notifications sent
+// during quiet hours
+type QuietHoursMode int
+
+const (
+	// QuietHoursDowngrade sends them with LevelPassive, so they arrive
+	// without sound or lighting up the screen
+	QuietHoursDowngrade QuietHoursMode = iota
+
+	// QuietHoursSuppress doesn't send them at all. The send succeeds with
+	// SendResult.Suppressed set.
+	QuietHoursSuppress
+)
+
+// quietHours is the quiet-hours policy of the client
+type quietHours struct {
+	// start and end are offsets from midnight; the window wraps around
+	// midnight when start is after end
+	start, end time.Duration
+	location   *time.Location
+	mode       QuietHoursMode
+}
+
+// WithQuietHours keeps non-critical notifications from alerting at night.
+// From start until end, both time of day offsets from midnight in location,
+// they are downgraded to LevelPassive or suppressed, depending on mode.
+// Critical notifications always go through unchanged. The window may wrap
+// around midnight:
+//
+//	bark.WithQuietHours(22*time.Hour, 7*time.Hour, tz, bark.QuietHoursSuppress)
+//
+// A nil location uses time.Local, and equal start and end disable quiet
+// hours.
+func WithQuietHours(start, end time.Duration, location *time.Location, mode QuietHoursMode) Option {
+	return func(c *Client) {
+		if location == nil {
+			location = time.Local
+		}
+		c.quietHours = &quietHours{start: start, end: end, location: location, mode: mode}
+	}
+}
+
+// active reports whether t is within the quiet hours
+func (q *quietHours) active(t time.Time) bool {
+	if q.start == q.end {
+		return false
+	}
+
+	t = t.In(q.location)
+	hour, minute, sec := t.Clock()
+	now := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(sec)*time.Second
+	if q.start < q.end {
+		return now >= q.start && now < q.end
+	}
+	return now >= q.start || now < q.end
+}
+
+// applyQuietHours applies the quiet-hours policy to options, which already
+// carry the client's defaults. suppress is true when the notification must
+// not be sent.
+func (c *Client) applyQuietHours(options NotificationOptions) (_ NotificationOptions, suppress bool) {
+	q := c.quietHours
+	if q == nil || options.Level == LevelCritical || !q.active(c.clock.Now()) {
+		return options, false
+	}
+	if q.mode == QuietHoursSuppress {
+		c.warn(WarningQuietHours, "suppressed during quiet hours")
+		return options, true
+	}
+	options.Level = LevelPassive
+	c.warn(WarningQuietHours, "downgraded to passive during quiet hours")
+	return options, false
+}
+
+// newSuppressedResult returns the result of a send dropped by quiet hours
+func newSuppressedResult() *SendResult {
+	return &SendResult{
+		Response:   &Response{Message: "suppressed during quiet hours"},
+		RequestID:  newRandomID(),
+		Suppressed: true,
+	}
+}
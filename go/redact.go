@@ -0,0 +1,35 @@
+package bark
+
+import "strings"
+
+// WithKeyRedaction sets how many trailing characters of the Bark key stay
+// visible wherever the key would otherwise surface, such as error messages.
+// keepSuffix 0, the default, masks the whole key. At most half of the key
+// is ever revealed.
+func WithKeyRedaction(keepSuffix int) Option {
+	return func(c *Client) {
+		if keepSuffix > 0 {
+			c.keepKeySuffix = keepSuffix
+		}
+	}
+}
+
+// redactedKey returns the masked form of key, e.g. "****DEFG"
+func (c *Client) redactedKey(key string) string {
+	keep := c.keepKeySuffix
+	if keep > len(key)/2 {
+		keep = len(key) / 2
+	}
+	if keep <= 0 {
+		return "***"
+	}
+	return "****" + key[len(key)-keep:]
+}
+
+// redact replaces every occurrence of key in s with its masked form
+func (c *Client) redact(s, key string) string {
+	if key == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, key, c.redactedKey(key))
+}
@@ -0,0 +1,60 @@
+package bark
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestKeyRedaction(t *testing.T) {
+	const key = "SECRETKEY123"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "drop") {
+			// Fail with a transport error, whose message quotes the URL
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		respondSuccess(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	var logs bytes.Buffer
+	client, err := NewClient(key, server.URL, WithKeyRedaction(3), WithDebugLogging(DebugFull), WithLogger(log.New(&logs, "", 0)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := client.Send(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := client.SendPost(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("SendPost: %v", err)
+	}
+	_, err = client.Send(NotificationOptions{Body: "drop"})
+	if err == nil {
+		t.Fatal("Send to a dropped connection succeeded, want a transport error")
+	}
+
+	for name, s := range map[string]string{
+		"logs":   logs.String(),
+		"error":  err.Error(),
+		"config": client.Config().Key,
+	} {
+		if strings.Contains(s, key) {
+			t.Errorf("%s reveals the key: %s", name, s)
+		}
+		if !strings.Contains(s, "****123") {
+			t.Errorf("%s = %q, want the redacted key ****123", name, s)
+		}
+	}
+
+	if got := client.redactedKey("abcd"); got != "****cd" {
+		t.Errorf("redactedKey(abcd) = %q, want at most half the key revealed", got)
+	}
+}
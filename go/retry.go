@@ -0,0 +1,172 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// JitterStrategy selects how randomness is applied to backoff delays
+type JitterStrategy int
+
+const (
+	// JitterFull picks a random delay between zero and the exponential delay
+	JitterFull JitterStrategy = iota
+
+	// JitterNone uses the exponential delay as is
+	JitterNone
+
+	// JitterEqual keeps half of the exponential delay and randomizes the rest
+	JitterEqual
+
+	// JitterDecorrelated picks a random delay between the base delay and
+	// three times the previous delay
+	JitterDecorrelated
+)
+
+// RetryPolicy controls how failed sends are retried.
+//
+// A send is retried when the request fails at the transport level (except
+// for unresolvable hostnames, see ErrDNS) or the server answers with 429 or
+// a 5xx status, or with one of the RetryCodes. The delays between attempts
+// are set by the embedded Backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// RetryCodes are API codes of the response body (Response.Code) that
+	// are retried as well, whatever the HTTP status, such as a busy code
+	// some servers answer with along with 200
+	RetryCodes []int
+
+	Backoff
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and DefaultBackoff
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     DefaultBackoff(),
+	}
+}
+
+// WithRetry enables retrying failed sends according to policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// isRetryable reports whether a failed attempt is worth retrying
+func isRetryable(err error) bool {
+	// The health check or circuit breaker may let the next attempt through
+	if errors.Is(err, ErrServerUnhealthy) || errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	// An unresolvable hostname is a configuration problem, retrying only
+	// helps when the resolver itself had a hiccup
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var barkErr *BarkError
+	if !errors.As(err, &barkErr) {
+		// Raw transport errors, see WithRawErrors
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+
+	// Failures of the client itself, such as a failed request modifier,
+	// would only fail again
+	return barkErr.transport ||
+		barkErr.StatusCode == http.StatusTooManyRequests ||
+		barkErr.StatusCode >= http.StatusInternalServerError
+}
+
+// WithMaxRetries retries failed sends up to n times, with DefaultBackoff
+// unless delays are set with WithBackoff or WithRetry. 0 disables retries.
+//
+// It takes precedence over the MaxAttempts of WithRetry, whatever the order
+// of the options, so policy and cap can be given separately.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n < 0 {
+			n = 0
+		}
+		c.maxRetries = &n
+	}
+}
+
+// WithBackoff sets the delays between retries. It takes precedence over the
+// Backoff of WithRetry, whatever the order of the options, and doesn't
+// enable retries by itself; combine it with WithMaxRetries or WithRetry.
+func WithBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.retryBackoff = &backoff
+	}
+}
+
+// resolveRetryPolicy merges WithMaxRetries and WithBackoff into the retry
+// policy once all options are applied
+func (c *Client) resolveRetryPolicy() {
+	if c.retryBackoff != nil {
+		c.retryPolicy.Backoff = *c.retryBackoff
+	}
+	if c.maxRetries != nil {
+		c.retryPolicy.MaxAttempts = *c.maxRetries + 1
+		if c.retryPolicy.Backoff == (Backoff{}) {
+			c.retryPolicy.Backoff = DefaultBackoff()
+		}
+	}
+}
+
+// retries reports whether the policy retries a send that failed with err
+func (p RetryPolicy) retries(err error) bool {
+	if isRetryable(err) {
+		return true
+	}
+
+	var barkErr *BarkError
+	if len(p.RetryCodes) == 0 || !errors.As(err, &barkErr) || barkErr.Response == nil {
+		return false
+	}
+	for _, code := range p.RetryCodes {
+		if barkErr.Response.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// sleep waits for d on the client's clock or until ctx is done, whichever
+// comes first. Every wait of the client goes through it, so cancelling ctx
+// interrupts backoffs right away.
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(d):
+		return nil
+	}
+}
+
+// interrupted returns err of a send that gave up because ctx is done, such
+// as while waiting to retry, wrapped so that it also matches ctx.Err()
+func interrupted(ctx context.Context, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+	return fmt.Errorf("%w, last attempt: %w", ctxErr, err)
+}
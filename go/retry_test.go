@@ -0,0 +1,103 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// blockingClock is a Clock whose waits never end on their own. It reports
+// every wait on waits, so tests can act while the client is waiting.
+type blockingClock struct {
+	waits chan time.Duration
+}
+
+func (c blockingClock) Now() time.Time        { return time.Now() }
+func (c blockingClock) Sleep(d time.Duration) { select {} }
+func (c blockingClock) After(d time.Duration) <-chan time.Time {
+	c.waits <- d
+	return make(chan time.Time)
+}
+
+func TestRetryBackoffCancelled(t *testing.T) {
+	clock := blockingClock{waits: make(chan time.Duration, 1)}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}, WithClock(clock), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     Backoff{BaseDelay: time.Hour, MaxDelay: time.Hour, Jitter: JitterNone},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var result *SendResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = client.SendWithResult(ctx, NotificationOptions{Body: "hello"})
+	}()
+
+	if d := <-clock.waits; d != time.Hour {
+		t.Errorf("waiting %v, want the backoff of 1h", d)
+	}
+	cancelled := time.Now()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("send still waiting a second after its context was cancelled")
+	}
+	if elapsed := time.Since(cancelled); elapsed > 100*time.Millisecond {
+		t.Errorf("send returned %v after cancellation, want near-immediate return", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if !errors.Is(err, ErrServerBusy) {
+		t.Errorf("err = %v, want the last attempt's ErrServerBusy too", err)
+	}
+	if result == nil || result.AttemptCount != 1 {
+		t.Errorf("result = %+v, want 1 attempt", result)
+	}
+}
+
+func TestRetryCodes(t *testing.T) {
+	const busyCode = 1001
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			_, _ = w.Write([]byte(`{"code":1001,"message":"server busy"}`))
+			return
+		}
+		respondSuccess(w, r)
+	}, WithClock(fixedClock{time.Now()}), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		RetryCodes:  []int{busyCode},
+		Backoff:     DefaultBackoff(),
+	}))
+
+	result, err := client.SendWithResult(context.Background(), NotificationOptions{Body: "hello"})
+	if err != nil {
+		t.Fatalf("SendWithResult: %v", err)
+	}
+	if result.AttemptCount != 3 || requests != 3 {
+		t.Errorf("AttemptCount = %d, requests = %d, want 3", result.AttemptCount, requests)
+	}
+	if result.Response.Code != http.StatusOK {
+		t.Errorf("Code = %d, want 200", result.Response.Code)
+	}
+
+	// Other codes are not retried
+	requests = 0
+	client.retryPolicy.RetryCodes = []int{2002}
+	if _, err := client.SendWithResult(context.Background(), NotificationOptions{Body: "hello"}); err == nil {
+		t.Error("SendWithResult succeeded, want the busy code's error")
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
@@ -0,0 +1,174 @@
+package bark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// serverInfoRetryAfter is how long a failed /info probe is remembered before
+// features depending on the server version probe again
+const serverInfoRetryAfter = 30 * time.Second
+
+// ServerInfo describes a Bark server, as reported by its /info endpoint
+type ServerInfo struct {
+	// Version is the server version, e.g. "v2.1.5"
+	Version string `json:"version"`
+
+	// Build is the server build date
+	Build string `json:"build"`
+
+	// Arch is the platform the server was built for
+	Arch string `json:"arch"`
+
+	// Commit is the source commit the server was built from
+	Commit string `json:"commit"`
+
+	// Devices is the number of devices registered on the server
+	Devices int `json:"devices"`
+
+	// Arguments lists the push parameters the server supports, e.g.
+	// "level". Only some servers advertise it, see WithStrictArguments.
+	Arguments []string `json:"arguments,omitempty"`
+}
+
+// serverInfoCache holds the last ServerInfo fetched from the server, or the
+// error of the last failed probe and when it failed
+type serverInfoCache struct {
+	mu       sync.Mutex
+	info     *ServerInfo
+	err      error
+	failedAt time.Time
+}
+
+// ServerInfo fetches information about the Bark server. The result is
+// cached and reused by features that depend on the server version.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.currentServerURL()+"/info", nil)
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
+		}
+	}
+
+	c.setAcceptLanguage(req)
+	if err := c.modifyRequest(req); err != nil {
+		return nil, err
+	}
+	resp, err := c.currentDoer().Do(req)
+	if err != nil {
+		return nil, newTransportError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, &BarkError{
+			Message:    fmt.Sprintf("failed to read response body: %v", err),
+			Err:        err,
+			StatusCode: resp.StatusCode,
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &BarkError{
+			Message:    fmt.Sprintf("server returned error: %s", strings.TrimSpace(string(body))),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	var info ServerInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, &BarkError{
+			Message:    fmt.Sprintf("failed to parse server info: %v", err),
+			Err:        err,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	c.serverInfo.mu.Lock()
+	c.serverInfo.info = &info
+	c.serverInfo.err = nil
+	c.serverInfo.mu.Unlock()
+
+	return &info, nil
+}
+
+// cachedServerInfo returns the cached ServerInfo, fetching it on first use.
+// A failed fetch is cached too and returned for serverInfoRetryAfter, so an
+// unreachable or missing /info doesn't cost an extra request on every send.
+// Failures caused by ctx itself are not cached.
+func (c *Client) cachedServerInfo(ctx context.Context) (*ServerInfo, error) {
+	c.serverInfo.mu.Lock()
+	info, err, failedAt := c.serverInfo.info, c.serverInfo.err, c.serverInfo.failedAt
+	c.serverInfo.mu.Unlock()
+
+	if info != nil {
+		return info, nil
+	}
+	if err != nil && c.clock.Now().Sub(failedAt) < serverInfoRetryAfter {
+		return nil, err
+	}
+
+	info, err = c.ServerInfo(ctx)
+	if err != nil && ctx.Err() == nil {
+		c.serverInfo.mu.Lock()
+		c.serverInfo.err = err
+		c.serverInfo.failedAt = c.clock.Now()
+		c.serverInfo.mu.Unlock()
+	}
+	return info, err
+}
+
+// AtLeast reports whether the server version is the given version or newer.
+// Versions are compared numerically by their dot-separated parts, ignoring a
+// leading "v". An unparseable server version is treated as older.
+func (i *ServerInfo) AtLeast(version string) bool {
+	have, ok := parseVersion(i.Version)
+	if !ok {
+		return false
+	}
+	want, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+
+	for n := 0; n < len(want); n++ {
+		var part int
+		if n < len(have) {
+			part = have[n]
+		}
+		if part != want[n] {
+			return part > want[n]
+		}
+	}
+	return true
+}
+
+// parseVersion splits a version like "v2.1.5" into its numeric parts
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return nil, false
+	}
+
+	fields := strings.Split(version, ".")
+	parts := make([]int, len(fields))
+	for n, field := range fields {
+		// Drop pre-release suffixes such as "-beta"
+		if i := strings.IndexAny(field, "-+"); i >= 0 {
+			field = field[:i]
+		}
+		part, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		parts[n] = part
+	}
+	return parts, true
+}
@@ -0,0 +1,42 @@
+package bark
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerInfoFailureCached(t *testing.T) {
+	var infoRequests, sends int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info" {
+			infoRequests++
+			http.NotFound(w, r)
+			return
+		}
+		sends++
+		respondSuccess(w, r)
+	}, WithFeatureGating(FeatureGatingDrop), WithLogger(log.New(io.Discard, "", 0)))
+
+	now := time.Now()
+	client.clock = fixedClock{now}
+	for i := 0; i < 5; i++ {
+		if _, err := client.Send(NotificationOptions{Body: "hello", Level: LevelCritical}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if infoRequests != 1 || sends != 5 {
+		t.Errorf("%d /info requests for %d sends, want 1 for 5", infoRequests, sends)
+	}
+
+	// The failure is probed again once it is old enough
+	client.clock = fixedClock{now.Add(serverInfoRetryAfter)}
+	if _, err := client.Send(NotificationOptions{Body: "hello", Level: LevelCritical}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if infoRequests != 2 {
+		t.Errorf("%d /info requests after the failure expired, want 2", infoRequests)
+	}
+}
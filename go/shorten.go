@@ -0,0 +1,51 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+)
+
+// URLShortener returns a short form of the tap URL long
+type URLShortener func(ctx context.Context, long string) (short string, err error)
+
+// WithURLShortener passes the URL of every notification through shorten
+// before it is sent, keeping GET URLs short for link-heavy notifications.
+// shorten may return long unchanged, e.g. for URLs that are already short.
+// If it fails, a warning is logged and the original URL is sent, unless
+// WithURLShortenerRequired is set.
+func WithURLShortener(shorten URLShortener) Option {
+	return func(c *Client) {
+		c.urlShortener = shorten
+	}
+}
+
+// WithURLShortenerRequired makes a failure of the URL shortener fail the send
+// instead of falling back to the original URL
+func WithURLShortenerRequired() Option {
+	return func(c *Client) {
+		c.urlShortenerRequired = true
+	}
+}
+
+// shortenURL applies the client's URL shortener to options
+func (c *Client) shortenURL(ctx context.Context, options NotificationOptions) (NotificationOptions, error) {
+	if c.urlShortener == nil || options.URL == "" {
+		return options, nil
+	}
+
+	short, err := c.urlShortener(ctx, options.URL)
+	if err != nil {
+		if c.urlShortenerRequired {
+			return options, &BarkError{
+				Message: fmt.Sprintf("failed to shorten URL: %v", err),
+				Err:     err,
+			}
+		}
+		c.warnf(WarningURLNotShortened, "sending the original URL, failed to shorten it: %v", err)
+		return options, nil
+	}
+	if short != "" {
+		options.URL = short
+	}
+	return options, nil
+}
@@ -0,0 +1,114 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClientClosed is returned when work is submitted to a client that has
+// been shut down
+var ErrClientClosed = errors.New("client is shut down")
+
+// UndeliveredError is returned by Shutdown when notifications were still
+// pending as its context expired
+type UndeliveredError struct {
+	// Pending are the notifications that were not delivered
+	Pending []NotificationOptions
+
+	// Err is the context error that ended the shutdown
+	Err error
+}
+
+// Error implements the error interface
+func (e *UndeliveredError) Error() string {
+	return fmt.Sprintf("shutdown left %d notifications undelivered: %v", len(e.Pending), e.Err)
+}
+
+// Unwrap returns the context error that ended the shutdown
+func (e *UndeliveredError) Unwrap() error {
+	return e.Err
+}
+
+// Shutdown stops the client from accepting queued sends and stops its health
+// check and latency prober, then waits until the queue worker has delivered
+// everything already queued and all dead-letter calls have returned, or
+// until ctx is done.
+//
+// If ctx expires first, the worker is stopped and the notifications not yet
+// delivered are returned in an *UndeliveredError. A queue whose worker was
+// never started is not drained; its contents are returned the same way.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.closed.Store(true)
+	c.stopHealthCheck()
+	c.stopLatencyRouting()
+
+	if q := c.queue; q != nil {
+		q.mu.Lock()
+		q.draining = true
+		done := q.done
+		q.mu.Unlock()
+
+		// Wake the worker so it notices it should exit once the queue is empty
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+
+		if done != nil {
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+		}
+		// Stops the worker if it is still running and waits for it
+		c.Stop()
+
+		if pending := q.drain(); len(pending) > 0 {
+			err := ctx.Err()
+			if err == nil {
+				err = errors.New("queue worker was not running")
+			}
+			return &UndeliveredError{Pending: pending, Err: err}
+		}
+	}
+
+	// Checked before waiting, so an already expired ctx doesn't fail a
+	// shutdown that has nothing left to wait for
+	if c.deadLettersRunning.Load() == 0 {
+		return nil
+	}
+	deadLettersDone := make(chan struct{})
+	go func() {
+		c.deadLetters.Wait()
+		close(deadLettersDone)
+	}()
+	select {
+	case <-deadLettersDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// Close shuts the client down without waiting for queued notifications and
+// releases its idle connections. Notifications still queued are returned in
+// an *UndeliveredError.
+func (c *Client) Close() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Shutdown(ctx)
+	if hc := c.currentHTTPClient(); hc != nil {
+		hc.CloseIdleConnections()
+	}
+
+	// With an already cancelled context the dead-letter wait always reports
+	// cancellation, which isn't a failure of Close
+	var undelivered *UndeliveredError
+	if errors.Is(err, context.Canceled) && !errors.As(err, &undelivered) {
+		return nil
+	}
+	return err
+}
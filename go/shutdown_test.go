@@ -0,0 +1,112 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownFlushesQueue(t *testing.T) {
+	var delivered atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		delivered.Add(1)
+		respondSuccess(w, r)
+	}, WithQueue(3))
+
+	for _, body := range []string{"one", "two", "three"} {
+		if err := client.Enqueue(NotificationOptions{Body: body}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	client.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if n := delivered.Load(); n != 3 {
+		t.Errorf("%d notifications delivered before Shutdown returned, want 3", n)
+	}
+	if err := client.Enqueue(NotificationOptions{Body: "late"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Enqueue after Shutdown: err = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestCloseRejectsQueuedWork(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent by a closed client")
+		respondSuccess(w, r)
+	}, WithQueue(2))
+
+	if err := client.Enqueue(NotificationOptions{Body: "pending"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	var undelivered *UndeliveredError
+	if err := client.Close(); !errors.As(err, &undelivered) {
+		t.Fatalf("Close: err = %v, want an *UndeliveredError", err)
+	}
+	if len(undelivered.Pending) != 1 || undelivered.Pending[0].Body != "pending" {
+		t.Errorf("Pending = %v, want the queued notification", undelivered.Pending)
+	}
+
+	if err := client.Enqueue(NotificationOptions{Body: "late"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Enqueue after Close: err = %v, want ErrClientClosed", err)
+	}
+	client.Start()
+	client.Stop()
+}
+
+func TestShutdownExpiredContext(t *testing.T) {
+	client := newTestClient(t, respondSuccess, WithQueue(1))
+	client.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown with nothing pending: err = %v, want nil", err)
+	}
+}
+
+func TestShutdownWaitsForDeadLetters(t *testing.T) {
+	release := make(chan struct{})
+	called := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}, WithQueue(1), WithClock(fixedClock{time.Now()}), WithDeadLetter(func(options NotificationOptions, lastErr error) {
+		close(called)
+		<-release
+	}))
+
+	if err := client.Enqueue(NotificationOptions{Body: "lost"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	client.Start()
+	<-called
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown with a running dead-letter call: err = %v, want context.DeadlineExceeded", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Errorf("Close with a running dead-letter call: err = %v, want nil", err)
+	}
+
+	close(release)
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown after the dead-letter call returned: err = %v, want nil", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	client := newTestClient(t, respondSuccess, WithQueue(1))
+	client.Start()
+	if err := client.Close(); err != nil {
+		t.Errorf("Close: err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,29 @@
+package bark
+
+import "context"
+
+// SendSilent is like SendWithResult but delivers the notification without
+// alerting the user: the level is LevelPassive, and Sound, Volume, Call and
+// Badge are cleared, whatever options, hooks or client defaults say. The
+// other fields are delivered as given, e.g. for automations reading a JSON
+// body.
+func (c *Client) SendSilent(ctx context.Context, options NotificationOptions) (*SendResult, error) {
+	return c.send(context.WithValue(ctx, silentSendKey{}, true), c.currentKey(), options, c.sendMode)
+}
+
+// silentSendKey marks the context of sends made by SendSilent
+type silentSendKey struct{}
+
+// applySilent strips everything that alerts the user from the options of a
+// SendSilent send
+func applySilent(ctx context.Context, options NotificationOptions) NotificationOptions {
+	if silent, _ := ctx.Value(silentSendKey{}).(bool); !silent {
+		return options
+	}
+	options.Level = LevelPassive
+	options.Sound = ""
+	options.Volume = nil
+	options.Call = false
+	options.Badge = nil
+	return options
+}
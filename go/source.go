@@ -0,0 +1,48 @@
+package bark
+
+import (
+	"context"
+	"os"
+)
+
+// WithSource tags every notification with the source that sent it, such as
+// the host name. An empty source uses os.Hostname(). The source becomes the
+// subtitle of titled notifications without one and is appended to the body
+// of the others as " [source]", since GET requests only carry a subtitle
+// along with a title. Encrypted notifications are only tagged through the
+// subtitle, and an empty body is never tagged, so it still fails with
+// ErrEmptyBody. ContextWithSource overrides the source per send.
+func WithSource(source string) Option {
+	return func(c *Client) {
+		if source == "" {
+			source, _ = os.Hostname()
+		}
+		c.source = source
+	}
+}
+
+// sourceKey is the context key of the source set with ContextWithSource
+type sourceKey struct{}
+
+// ContextWithSource returns a copy of ctx overriding the source set with
+// WithSource for the sends made with it. An empty source sends them untagged.
+func ContextWithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// applySource tags options with the source for ctx
+func (c *Client) applySource(ctx context.Context, options NotificationOptions) NotificationOptions {
+	source := c.source
+	if override, ok := ctx.Value(sourceKey{}).(string); ok {
+		source = override
+	}
+
+	switch {
+	case source == "":
+	case options.Subtitle == "" && options.Title != "":
+		options.Subtitle = source
+	case options.Ciphertext == "" && options.Body != "":
+		options.Body += " [" + source + "]"
+	}
+	return options
+}
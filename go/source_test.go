@@ -0,0 +1,45 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSendSource(t *testing.T) {
+	client, capture := newCaptureClient(t, WithSource("web-1"))
+
+	for _, tt := range []struct {
+		name    string
+		ctx     context.Context
+		options NotificationOptions
+		want    string
+	}{
+		{"untitled", context.Background(), NotificationOptions{Body: "hello"}, "/testkey/hello%20%5Bweb-1%5D"},
+		{"titled", context.Background(), NotificationOptions{Title: "t", Body: "hello"}, "/testkey/t/web-1/hello"},
+		{"subtitled", context.Background(), NotificationOptions{Title: "t", Subtitle: "s", Body: "hello"}, "/testkey/t/s/hello%20%5Bweb-1%5D"},
+		{"override", ContextWithSource(context.Background(), "job-7"), NotificationOptions{Body: "hello"}, "/testkey/hello%20%5Bjob-7%5D"},
+		{"disabled", ContextWithSource(context.Background(), ""), NotificationOptions{Body: "hello"}, "/testkey/hello"},
+	} {
+		if _, err := client.SendWithResult(tt.ctx, tt.options); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		req, _ := capture.LastRequest()
+		if got := req.URL.EscapedPath(); got != tt.want {
+			t.Errorf("%s: path = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendSourceEmptyBody(t *testing.T) {
+	client, capture := newCaptureClient(t, WithSource("web-1"))
+
+	for _, options := range []NotificationOptions{{}, {Title: "t"}} {
+		if _, err := client.SendWithResult(context.Background(), options); !errors.Is(err, ErrEmptyBody) {
+			t.Errorf("%+v: err = %v, want ErrEmptyBody", options, err)
+		}
+	}
+	if requests := capture.Requests(); len(requests) != 0 {
+		t.Errorf("%d requests sent, want none", len(requests))
+	}
+}
@@ -0,0 +1,46 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnknownTargetGroup is returned by SendToGroup for a name that was never
+// registered with RegisterGroup
+var ErrUnknownTargetGroup = errors.New("unknown target group")
+
+// targetGroups maps names of recipient groups to their keys
+type targetGroups struct {
+	mu     sync.RWMutex
+	groups map[string][]string
+}
+
+// RegisterGroup registers a named group of recipient keys, such as
+// "oncall-primary", for SendToGroup. Registering a name again replaces its
+// keys. These groups are recipient lists kept by the client; they are not
+// related to the Group field of a notification.
+func (c *Client) RegisterGroup(name string, keys []string) {
+	c.targets.mu.Lock()
+	defer c.targets.mu.Unlock()
+
+	if c.targets.groups == nil {
+		c.targets.groups = make(map[string][]string)
+	}
+	c.targets.groups[name] = append([]string(nil), keys...)
+}
+
+// SendToGroup sends the notification to every key of the named group
+// registered with RegisterGroup, like SendBatch. An unknown name returns an
+// error matching ErrUnknownTargetGroup.
+func (c *Client) SendToGroup(ctx context.Context, name string, options NotificationOptions) (BatchResults, error) {
+	c.targets.mu.RLock()
+	keys, ok := c.targets.groups[name]
+	c.targets.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTargetGroup, name)
+	}
+	return c.SendBatch(ctx, keys, options)
+}
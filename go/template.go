@@ -0,0 +1,67 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateRecipient is a single recipient of a templated notification
+type TemplateRecipient struct {
+	// Key is the recipient's Bark key
+	Key string
+
+	// Data is the value the body template is executed with for this recipient
+	Data map[string]interface{}
+}
+
+// SendTemplate renders tmpl with text/template once per recipient and sends
+// the result as the notification body to that recipient's key.
+//
+// All other fields are taken from options; options.Body is ignored. The
+// options parameter exists because the template only renders the body: the
+// title, group, sound and every other field are shared by all recipients and
+// would otherwise have no way in.
+// Recipients are processed concurrently. A failure for one recipient
+// (rendering or sending) is recorded in its BatchResult and does not stop
+// the remaining sends. The returned error is only non-nil when the template
+// itself cannot be parsed.
+func (c *Client) SendTemplate(ctx context.Context, tmpl string, recipients []TemplateRecipient, options NotificationOptions) (BatchResults, error) {
+	t, err := template.New("body").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, &BarkError{
+			Message: fmt.Sprintf("failed to parse template: %v", err),
+			Err:     err,
+		}
+	}
+
+	ctx = c.withRetryBudget(ctx)
+	results := make(BatchResults, len(recipients))
+	fanOut(len(recipients), defaultBatchConcurrency, func(i int) {
+		recipient := recipients[i]
+		results[i].Key = recipient.Key
+
+		if recipient.Key == "" {
+			results[i].Err = ErrEmptyKey
+			return
+		}
+
+		var body strings.Builder
+		if err := t.Execute(&body, recipient.Data); err != nil {
+			results[i].Err = &BarkError{
+				Message: fmt.Sprintf("failed to render template: %v", err),
+				Err:     err,
+			}
+			return
+		}
+
+		// Rendered bodies are sent over POST so that newlines and slashes
+		// introduced by the template don't have to survive the URL path
+		opts := options.Clone()
+		opts.Body = body.String()
+		results[i].Response, results[i].Err = c.sendPost(ctx, recipient.Key, opts)
+	})
+
+	return results, nil
+}
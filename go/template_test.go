@@ -0,0 +1,56 @@
+package bark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSendTemplate(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]NotificationOptions)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var options NotificationOptions
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		received[strings.Trim(r.URL.Path, "/")] = options
+		mu.Unlock()
+		respondSuccess(w, r)
+	})
+
+	results, err := client.SendTemplate(context.Background(), "Hi {{.name}}, the build failed", []TemplateRecipient{
+		{Key: "alice", Data: map[string]interface{}{"name": "Alice"}},
+		{Key: "bob", Data: map[string]interface{}{"name": "Bob"}},
+		{Key: "carol", Data: map[string]interface{}{}},
+		{Data: map[string]interface{}{"name": "Nobody"}},
+	}, NotificationOptions{Title: "CI", Body: "ignored"})
+	if err != nil {
+		t.Fatalf("SendTemplate: %v", err)
+	}
+
+	for _, key := range []string{"alice", "bob"} {
+		name := strings.ToUpper(key[:1]) + key[1:]
+		if got := received[key]; got.Title != "CI" || got.Body != "Hi "+name+", the build failed" {
+			t.Errorf("%s received %+v, want the rendered body with the shared title", key, got)
+		}
+	}
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("results = %v, want alice and bob to succeed", results)
+	}
+	if _, ok := received["carol"]; ok || results[2].Err == nil {
+		t.Errorf("carol: result %+v, want a render error and no request", results[2])
+	}
+	if !errors.Is(results[3].Err, ErrEmptyKey) {
+		t.Errorf("empty key: err = %v, want ErrEmptyKey", results[3].Err)
+	}
+
+	if _, err := client.SendTemplate(context.Background(), "{{.name", nil, NotificationOptions{}); err == nil {
+		t.Error("SendTemplate with an invalid template succeeded, want a parse error")
+	}
+}
@@ -0,0 +1,55 @@
+package bark
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithTestMode makes the client run validation and build every request as
+// usual but never send it, for exercising alerting code in CI. Each request
+// is answered with a synthetic success Response that has TestMode set and
+// the request that would have been sent in TestRequest.
+func WithTestMode() Option {
+	return func(c *Client) {
+		c.testMode = true
+	}
+}
+
+// TestRequest is a request built by a client in test mode instead of sending
+// it. URL contains the key as it would have been sent.
+type TestRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// testResponse answers req in test mode without sending it
+func (c *Client) testResponse(req *http.Request) (*Response, error) {
+	built := &TestRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, &BarkError{
+				Message: fmt.Sprintf("failed to read request body: %v", err),
+				Err:     err,
+			}
+		}
+		built.Body = body
+	}
+
+	return &Response{
+		Code:        http.StatusOK,
+		Message:     "success",
+		Timestamp:   c.clock.Now().Unix(),
+		StatusCode:  http.StatusOK,
+		TestMode:    true,
+		TestRequest: built,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrH2CUnsupported is returned by NewClient when WithHTTP2(true) is used
+// with a client built by a Go toolchain older than 1.24
+var ErrH2CUnsupported = errors.New("h2c requires building with Go 1.24 or later")
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it, and so can a mock
+// returning canned responses in unit tests.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WithHTTPDoer makes the client send its requests with doer instead of
+// HTTPClient, which is then ignored, as are the options configuring its
+// transport such as WithHTTP2.
+func WithHTTPDoer(doer HTTPDoer) Option {
+	return func(c *Client) {
+		c.doer = doer
+	}
+}
+
+// currentDoer returns what the client sends its requests with
+func (c *Client) currentDoer() HTTPDoer {
+	if c.doer != nil {
+		return c.doer
+	}
+	return c.currentHTTPClient()
+}
+
+// WithHTTP2 makes the client speak HTTP/2 to the server. With h2c false,
+// HTTP/2 is negotiated over TLS for https server URLs, falling back to
+// HTTP/1.1. With h2c true, http server URLs use HTTP/2 cleartext with prior
+// knowledge, for self-hosted servers running h2c behind a service mesh;
+// such servers must accept h2c, as HTTP/1.1 is no longer offered.
+//
+// h2c relies on net/http's Protocols and needs Go 1.24 or later to build
+// the client. With older toolchains NewClient returns ErrH2CUnsupported
+// when h2c is true.
+//
+// By default the client uses net/http's standard protocol selection.
+func WithHTTP2(h2c bool) Option {
+	return func(c *Client) {
+		t := c.transport()
+		t.ForceAttemptHTTP2 = true
+		c.h2c = h2c
+		configureHTTP2(t, h2c)
+	}
+}
+
+// transport returns the client's *http.Transport for options to configure,
+// installing a clone of http.DefaultTransport if the HTTP client has none.
+// A custom RoundTripper of another type is replaced.
+func (c *Client) transport() *http.Transport {
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	if t, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+		return t
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	c.HTTPClient.Transport = t
+	return t
+}
+
+// WithDisableKeepAlives closes the connection after every request instead
+// of keeping it open for reuse. It suits short-lived processes, such as
+// serverless functions sending a single notification. Batch senders
+// shouldn't use it, since every request then pays for a new connection.
+func WithDisableKeepAlives() Option {
+	return func(c *Client) {
+		c.transport().DisableKeepAlives = true
+	}
+}
+
+// WithDialContext sets the function used to open connections to the server,
+// for example to pin the source address or use a custom resolver. By default
+// the standard net.Dialer is used.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *Client) {
+		c.transport().DialContext = dial
+	}
+}
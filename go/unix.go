@@ -0,0 +1,57 @@
+package bark
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// unixScheme prefixes server URLs pointing at a unix domain socket
+	unixScheme = "unix://"
+
+	// unixServerURL replaces a unix socket server URL, so requests are
+	// built as usual and routed to the socket by the dialer
+	unixServerURL = "http://unix"
+)
+
+// useUnixSocket makes a server URL like unix:///tmp/bark.sock talk HTTP over
+// that socket. ServerURL is rewritten to http://unix and connections to that
+// host are dialed to the socket; other hosts are dialed as before.
+func (c *Client) useUnixSocket() error {
+	if !strings.HasPrefix(c.ServerURL, unixScheme) {
+		return nil
+	}
+
+	socketPath := strings.TrimPrefix(c.ServerURL, unixScheme)
+	if socketPath == "" {
+		return fmt.Errorf("invalid server URL %q: missing socket path", c.ServerURL)
+	}
+	c.ServerURL = unixServerURL
+
+	t := c.transport()
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == "unix:80" {
+			return dial(ctx, "unix", socketPath)
+		}
+		return dial(ctx, network, addr)
+	}
+
+	// The socket is local, never send its requests through a proxy
+	if proxy := t.Proxy; proxy != nil {
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			if req.URL.Host == "unix" {
+				return nil, nil
+			}
+			return proxy(req)
+		}
+	}
+	return nil
+}
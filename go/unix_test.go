@@ -0,0 +1,44 @@
+package bark
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "bark.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	var path string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		respondSuccess(w, r)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("testkey", "unix://"+socketPath)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if client.ServerURL != unixServerURL {
+		t.Errorf("ServerURL = %q, want %q", client.ServerURL, unixServerURL)
+	}
+	if _, err := client.SendPost(NotificationOptions{Body: "hello"}); err != nil {
+		t.Fatalf("SendPost: %v", err)
+	}
+	if path != "/testkey" {
+		t.Errorf("path = %q, want /testkey", path)
+	}
+
+	if _, err := NewClient("testkey", "unix://"); err == nil {
+		t.Error("NewClient without a socket path succeeded, want an error")
+	}
+}
@@ -0,0 +1,180 @@
+package bark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidSound is returned in strict mode when the sound is not one of the
+// Bark app's built-in sounds
+var ErrInvalidSound = errors.New("invalid sound. must be one of the built-in Bark sounds in strict mode")
+
+// ErrCallWithoutSound is returned in strict mode when Call is set without a
+// Sound, which would repeat the device's default sound
+var ErrCallWithoutSound = errors.New("call requires a sound in strict mode")
+
+// ErrBodyEmptyAfterSanitize is returned when WithSanitizeBody strips every
+// character of a non-empty body
+var ErrBodyEmptyAfterSanitize = errors.New("notification body is empty after removing control characters")
+
+// ValidationError describes a notification field that failed validation,
+// e.g. to highlight the offending input in a form. errors.Is matches the
+// sentinel error of the check, such as ErrInvalidLevel. When several fields
+// are invalid, their ValidationErrors are returned joined with errors.Join.
+type ValidationError struct {
+	// Field is the parameter name of the field, e.g. "level"
+	Field string
+
+	// Reason says what is wrong with it, e.g. "must not be negative"
+	Reason string
+
+	// Err is the sentinel error of the failed check, possibly wrapped with
+	// details
+	Err error
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Unwrap returns the sentinel error of the failed check
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks options the way a send would, with the client's defaults
+// applied, without sending anything. The error describes every invalid
+// field, see ValidationError.
+func (c *Client) Validate(options NotificationOptions) error {
+	_, err := c.prepareOptions(options)
+	return err
+}
+
+// builtinSounds are the sounds shipped with the Bark app
+var builtinSounds = map[string]bool{
+	"alarm": true, "anticipate": true, "bell": true, "birdsong": true,
+	"bloom": true, "calypso": true, "chime": true, "choo": true,
+	"descent": true, "electronic": true, "fanfare": true, "glass": true,
+	"gotosleep": true, "healthnotification": true, "horn": true, "ladder": true,
+	"mailsent": true, "minuet": true, "multiwayinvitation": true, "newmail": true,
+	"newsflash": true, "noir": true, "paymentsuccess": true, "shake": true,
+	"sherwoodforest": true, "silence": true, "spell": true, "suspense": true,
+	"telegraph": true, "tiptoes": true, "typewriters": true, "update": true,
+}
+
+// DefaultGroupMaxLength is the group length limit applied in strict mode
+// when none is set with WithGroupMaxLength
+const DefaultGroupMaxLength = 64
+
+// WithStrictValidation makes validation reject options that would otherwise
+// be adjusted or passed through as is. For example, a group longer than the
+// maximum length is rejected with ErrInvalidGroup instead of being truncated,
+// sounds other than the Bark app's built-in ones (custom sounds included)
+// are rejected with ErrInvalidSound, and Call without a Sound is rejected
+// with ErrCallWithoutSound.
+func WithStrictValidation() Option {
+	return func(c *Client) {
+		c.strictValidation = true
+	}
+}
+
+// WithAllowVolumeAllLevels accepts a Volume on notifications of any level,
+// for server forks that apply it to regular notifications too. The volume
+// must still be between 0 and 10.
+func WithAllowVolumeAllLevels() Option {
+	return func(c *Client) {
+		c.allowVolumeAllLevels = true
+	}
+}
+
+// WithGroupMaxLength limits groups to n characters. Longer groups are
+// truncated, or rejected with ErrInvalidGroup in strict mode.
+func WithGroupMaxLength(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.groupMaxLength = n
+		}
+	}
+}
+
+// WithSanitizeBody removes control characters other than newlines and tabs
+// from bodies before sending, such as NUL bytes or the escape characters
+// of captured terminal output. A body made up of control characters only is rejected
+// with ErrBodyEmptyAfterSanitize.
+func WithSanitizeBody() Option {
+	return func(c *Client) {
+		c.sanitizeBody = true
+	}
+}
+
+// sanitizeBody removes the control characters of body except newlines and tabs
+func sanitizeBody(body string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, body)
+}
+
+// validateGroup checks that a group keeps notification threading intact:
+// no control characters such as newlines and, in strict mode, no more than
+// the maximum length
+func (c *Client) validateGroup(group string) error {
+	for _, r := range group {
+		if unicode.IsControl(r) {
+			return &ValidationError{
+				Field:  "group",
+				Reason: fmt.Sprintf("must not contain control characters, contains %q", r),
+				Err:    fmt.Errorf("%w: contains %q", ErrInvalidGroup, r),
+			}
+		}
+	}
+
+	if c.strictValidation {
+		maxLength := c.groupMaxLength
+		if maxLength == 0 {
+			maxLength = DefaultGroupMaxLength
+		}
+		if n := utf8.RuneCountInString(group); n > maxLength {
+			return &ValidationError{
+				Field:  "group",
+				Reason: fmt.Sprintf("must be at most %d characters, has %d", maxLength, n),
+				Err:    fmt.Errorf("%w: %d characters, at most %d allowed", ErrInvalidGroup, n, maxLength),
+			}
+		}
+	}
+	return nil
+}
+
+// validateSound checks, in strict mode, that sound is a built-in Bark sound.
+// Names are matched case-insensitively and may carry the ".caf" extension.
+func (c *Client) validateSound(sound string) error {
+	if !c.strictValidation {
+		return nil
+	}
+	name := strings.TrimSuffix(strings.ToLower(sound), ".caf")
+	if !builtinSounds[name] {
+		return &ValidationError{
+			Field:  "sound",
+			Reason: "must be one of the built-in Bark sounds in strict mode",
+			Err:    fmt.Errorf("%w: %q", ErrInvalidSound, sound),
+		}
+	}
+	return nil
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
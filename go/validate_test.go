@@ -0,0 +1,37 @@
+package bark
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSanitizeBodyAllControlCharacters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+		respondSuccess(w, r)
+	}, WithSanitizeBody())
+
+	_, err := client.Send(NotificationOptions{Body: "\x00\x1b\x07\x7f\r"})
+	if !errors.Is(err, ErrBodyEmptyAfterSanitize) {
+		t.Errorf("err = %v, want ErrBodyEmptyAfterSanitize", err)
+	}
+	if errors.Is(err, ErrEmptyBody) {
+		t.Errorf("err = %v, want it not to match ErrEmptyBody", err)
+	}
+}
+
+func TestSanitizeBodyKeepsText(t *testing.T) {
+	var body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body = receivedParams(t, r)["body"]
+		respondSuccess(w, r)
+	}, WithSanitizeBody())
+
+	if _, err := client.SendPost(NotificationOptions{Body: "\x1b[31mred\x1b[0m\n\tdone\x00"}); err != nil {
+		t.Fatalf("SendPost: %v", err)
+	}
+	if want := "[31mred[0m\n\tdone"; body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
@@ -0,0 +1,96 @@
+package bark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Ping checks that the Bark server is reachable and answering
+func (c *Client) Ping(ctx context.Context) error {
+	return c.ping(ctx, c.currentServerURL())
+}
+
+// ping checks that the Bark server at serverURL is reachable and answering
+func (c *Client) ping(ctx context.Context, serverURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL+"/ping", nil)
+	if err != nil {
+		return &BarkError{
+			Message: fmt.Sprintf("failed to create request: %v", err),
+			Err:     err,
+		}
+	}
+
+	if err := c.modifyRequest(req); err != nil {
+		return err
+	}
+
+	key := c.currentKey()
+	_, _, err = c.do(req, key)
+	return c.surfaceError(err, key)
+}
+
+// WithOfflineVerify makes Verify skip its network check, so it can run
+// without reaching the server
+func WithOfflineVerify() Option {
+	return func(c *Client) {
+		c.offlineVerify = true
+	}
+}
+
+// Verify checks the whole client configuration: the key, the server URL, the
+// configured options and, unless WithOfflineVerify is set, that the server
+// answers a ping. All problems found are returned joined in one error.
+//
+// It is meant as a startup self-check before serving traffic.
+func (c *Client) Verify(ctx context.Context) error {
+	var errs []error
+
+	if key := c.currentKey(); key == "" {
+		errs = append(errs, ErrEmptyKey)
+	} else if err := c.validateKey(key); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, serverURL := range c.servers() {
+		if err := validateServerURL(serverURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.doer == nil && c.currentHTTPClient() == nil {
+		errs = append(errs, errors.New("HTTP client cannot be nil"))
+	}
+	if err := c.validateDefaults(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(c.successStatusCodes) == 0 {
+		errs = append(errs, errors.New("no success status codes configured"))
+	}
+	if c.retryPolicy.MaxAttempts > 1 && c.retryPolicy.MaxDelay < c.retryPolicy.BaseDelay {
+		errs = append(errs, errors.New("retry policy max delay is shorter than its base delay"))
+	}
+
+	// Only reach out to the server once the configuration itself is sound
+	if len(errs) == 0 && !c.offlineVerify {
+		if err := c.Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("server is not reachable: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateServerURL checks that serverURL is an absolute http or https URL
+func validateServerURL(serverURL string) error {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid server URL %q: must be an absolute http or https URL", serverURL)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package bark
+
+import (
+	"context"
+	"time"
+)
+
+// Reasons passed to the WithOnWait callback
+const (
+	// WaitRetry is the backoff before retrying a failed request
+	WaitRetry = "retry"
+
+	// WaitFailover is the backoff set with WithFailoverBackoff before trying
+	// the next server
+	WaitFailover = "failover"
+
+	// WaitQueueRetry is the send queue's backoff before redelivering a
+	// notification
+	WaitQueueRetry = "queue_retry"
+
+	// WaitConcurrency is the wait for a free slot under WithMaxConcurrency.
+	// Its length isn't known in advance, so it is reported as zero.
+	WaitConcurrency = "concurrency"
+)
+
+// WithOnWait calls fn whenever a send is about to wait, with the reason,
+// one of the Wait constants, and the length of the wait, e.g. to show
+// throttled or backed off sends on a dashboard. fn is called on the send
+// path and must return quickly.
+func WithOnWait(fn func(reason string, d time.Duration)) Option {
+	return func(c *Client) {
+		c.onWait = fn
+	}
+}
+
+// notifyWait reports a wait to the WithOnWait callback
+func (c *Client) notifyWait(reason string, d time.Duration) {
+	if c.onWait != nil {
+		c.onWait(reason, d)
+	}
+}
+
+// wait is like sleep, reporting the wait to the WithOnWait callback first
+func (c *Client) wait(ctx context.Context, reason string, d time.Duration) error {
+	if d > 0 && ctx.Err() == nil {
+		c.notifyWait(reason, d)
+	}
+	return c.sleep(ctx, d)
+}
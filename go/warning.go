@@ -0,0 +1,82 @@
+package bark
+
+import "fmt"
+
+// WarningCode identifies the kind of a Warning
+type WarningCode string
+
+// Codes of the warnings passed to the WithWarningHandler handler
+const (
+	// WarningFeatureDropped means WithFeatureGating removed a parameter the
+	// server is too old for
+	WarningFeatureDropped WarningCode = "feature_dropped"
+
+	// WarningCheckSkipped means a check against the server info, such as
+	// feature gating or WithStrictArguments, was skipped because the info
+	// couldn't be fetched
+	WarningCheckSkipped WarningCode = "check_skipped"
+
+	// WarningActionsDropped means Actions were replaced by a URL because
+	// they can't be delivered to the server
+	WarningActionsDropped WarningCode = "actions_dropped"
+
+	// WarningQuietHours means a notification was downgraded to LevelPassive
+	// or suppressed by WithQuietHours
+	WarningQuietHours WarningCode = "quiet_hours"
+
+	// WarningBodySanitized means WithSanitizeBody removed characters from
+	// the body
+	WarningBodySanitized WarningCode = "body_sanitized"
+
+	// WarningGroupTruncated means the group was cut to the maximum length
+	WarningGroupTruncated WarningCode = "group_truncated"
+
+	// WarningURLNotShortened means the URL shortener failed and the original
+	// URL was sent
+	WarningURLNotShortened WarningCode = "url_not_shortened"
+
+	// WarningKeyDisabled means WithDisableOnInvalidKey disabled a key
+	WarningKeyDisabled WarningCode = "key_disabled"
+)
+
+// Warning describes something the client changed or skipped without
+// failing the send
+type Warning struct {
+	Code    WarningCode
+	Message string
+}
+
+// WithWarningHandler calls fn for every Warning, whenever the client changes
+// or skips something without failing the send, e.g. to count them in
+// metrics. Warnings that were already logged still are. fn is called on the
+// send path and must return quickly.
+func WithWarningHandler(fn func(Warning)) Option {
+	return func(c *Client) {
+		c.warningHandler = fn
+	}
+}
+
+// warn passes a warning to the WithWarningHandler handler
+func (c *Client) warn(code WarningCode, message string) {
+	if c.warningHandler != nil {
+		c.warningHandler(Warning{Code: code, Message: message})
+	}
+}
+
+// warnf logs a warning and passes it to the WithWarningHandler handler
+func (c *Client) warnf(code WarningCode, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	c.logf("bark: %s", message)
+	c.warn(code, message)
+}
+
+// warnAdjusted reports the changes prepareOptions made to before, the
+// options of a send, that it turned into after
+func (c *Client) warnAdjusted(before, after NotificationOptions) {
+	if before.Body != after.Body {
+		c.warn(WarningBodySanitized, "removed control characters from the body")
+	}
+	if before.Group != "" && before.Group != after.Group {
+		c.warn(WarningGroupTruncated, fmt.Sprintf("truncated the group to %d characters", c.groupMaxLength))
+	}
+}